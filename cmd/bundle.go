@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+var packOutFile string
+
+var packCmd = &cobra.Command{
+	Use:   "pack [path]",
+	Short: "Write the schemas of a workspace into a portable bundle",
+	Long: `Write the provider schemas of a Terraform working directory, together with their
+provenance (tool version, provider sources, versions and lockfile hashes), into a
+single file that can be copied to machines without registry access or credentials.
+
+The schemas are taken from the cache when possible, exactly as the explorer would
+load them. Open a bundle with --bundle, or load it into the local cache with unpack.`,
+	Example: `  provider-explorer pack -o schemas.bundle
+  provider-explorer unpack schemas.bundle
+  provider-explorer --bundle schemas.bundle`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPack,
+}
+
+var unpackCmd = &cobra.Command{
+	Use:   "unpack <bundle>",
+	Short: "Load the provider schemas of a bundle into the cache",
+	Long: `Load the provider schemas of a bundle into the per-provider cache. Workspaces that
+lock the same provider versions then open without init or registry access.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUnpack,
+}
+
+func init() {
+	packCmd.Flags().StringVarP(&packOutFile, "out", "o", "schemas.bundle", "bundle file to write")
+	rootCmd.AddCommand(packCmd, unpackCmd)
+}
+
+func runPack(cmd *cobra.Command, args []string) error {
+	workingDir := "."
+	if len(args) > 0 {
+		workingDir = args[0]
+	}
+	absPath, err := filepath.Abs(workingDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	tfInfo, err := resolveToolInfo()
+	if err != nil {
+		return err
+	}
+
+	schemaWithVersion, err := terraform.FetchAllProviderSchemas(absPath, tfInfo)
+	if err != nil {
+		return err
+	}
+	bundle, err := terraform.NewBundle(absPath, schemaWithVersion)
+	if err != nil {
+		return err
+	}
+	if err := terraform.WriteBundle(packOutFile, bundle); err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Packed %d provider schema(s) into %s\n", len(bundle.Provenance.Providers), packOutFile)
+	return writeBundleProviders(out, bundle)
+}
+
+func runUnpack(cmd *cobra.Command, args []string) error {
+	bundle, err := terraform.ReadBundle(args[0])
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Bundle:\t%s\n", args[0])
+	fmt.Fprintf(tw, "Packed:\t%s on %s\n", bundle.CreatedAt.Local().Format(time.RFC3339), bundle.Provenance.Platform)
+	fmt.Fprintf(tw, "Tool:\t%s\n", strings.TrimSpace(bundle.Provenance.Tool+" "+bundle.Provenance.ToolVersion))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if err := writeBundleProviders(out, bundle); err != nil {
+		return err
+	}
+
+	keys, err := bundle.Unpack()
+	for _, key := range keys {
+		fmt.Fprintf(out, "Cached %s\n", key)
+	}
+	if err != nil {
+		return err
+	}
+	if skipped := len(bundle.Provenance.Providers) - len(keys); skipped > 0 {
+		fmt.Fprintf(out, "Skipped %d provider schema(s) without a version; open the bundle with --bundle to browse them\n", skipped)
+	}
+	return nil
+}
+
+// writeBundleProviders lists the providers of a bundle with their versions and hash counts
+func writeBundleProviders(w io.Writer, bundle *terraform.Bundle) error {
+	fmt.Fprintln(w, "\nProviders:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, p := range bundle.Provenance.Providers {
+		version := p.Version
+		if version == "" {
+			version = "-"
+		}
+		hashes := "not locked"
+		if len(p.Hashes) > 0 {
+			hashes = fmt.Sprintf("%d lockfile hash(es)", len(p.Hashes))
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", p.Source, version, hashes)
+	}
+	return tw.Flush()
+}
+
+// runBundleTUI starts the explorer on the schemas of a bundle. Like --schema-file it
+// needs no configuration, init or credentials; feature gating follows the tool version
+// the bundle was packed with.
+func runBundleTUI(path string, tfInfo terraform.TerraformInfo, link *ui.DeepLink) error {
+	bundle, err := terraform.ReadBundle(path)
+	if err != nil {
+		return fmt.Errorf("failed to load bundle: %w", err)
+	}
+	if bundle.Schema.TfInfo.Tool == "" {
+		bundle.Schema.TfInfo = tfInfo
+	}
+
+	model := ui.NewModelFromLoader(func() (*terraform.SchemaWithVersionInfo, error) {
+		return bundle.Schema, nil
+	}, 80, 24)
+	if link != nil {
+		model.SetDeepLink(*link)
+	}
+	return runProgram(model)
+}
@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+var cacheOlderThan string
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clean up cached provider schemas",
+	Long: `Inspect and clean up the provider schemas cached in ~/.resource-cache.
+
+Workspace entries are identified by their cache key; any unambiguous prefix of a key
+may be used. Provider entries, shared by every workspace that locks the same provider
+version, are identified by source@version (e.g. hashicorp/aws@6.0.0).`,
+}
+
+var cacheLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List cached provider schemas",
+	Args:    cobra.NoArgs,
+	RunE:    runCacheLs,
+}
+
+var cacheShowCmd = &cobra.Command{
+	Use:   "show <key>",
+	Short: "Show where a cached schema came from and what it contains",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCacheShow,
+}
+
+var cacheRmCmd = &cobra.Command{
+	Use:   "rm <key>...",
+	Short: "Remove cached schemas",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runCacheRm,
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:     "prune",
+	Short:   "Remove cached schemas older than a given age",
+	Example: `  provider-explorer cache prune --older-than 30d`,
+	Args:    cobra.NoArgs,
+	RunE:    runCachePrune,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached schemas",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheClear,
+}
+
+func init() {
+	cachePruneCmd.Flags().StringVar(&cacheOlderThan, "older-than", "30d", "minimum age of entries to remove (e.g. 12h, 30d, 2w)")
+	cacheCmd.AddCommand(cacheLsCmd, cacheShowCmd, cacheRmCmd, cachePruneCmd, cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheLs(cmd *cobra.Command, args []string) error {
+	entries, err := terraform.ListCacheEntries()
+	if err != nil {
+		return err
+	}
+	providers, err := terraform.ListProviderCacheEntries()
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if len(entries) == 0 && len(providers) == 0 {
+		_, err := fmt.Fprintln(out, "No cached schemas")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if len(entries) > 0 {
+		fmt.Fprintln(tw, "KEY\tSIZE\tAGE\tTOOL\tSOURCE")
+		for _, e := range entries {
+			tool, source := "-", "-"
+			if e.Metadata != nil {
+				tool = strings.TrimSpace(e.Metadata.Tool + " " + e.Metadata.ToolVersion)
+				source = e.Metadata.SourceDir
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", shortKey(e.Key), formatSize(e.Size), formatAge(time.Since(e.CreatedAt())), tool, source)
+		}
+	}
+	if len(providers) > 0 {
+		if len(entries) > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintln(tw, "PROVIDER\tSIZE\tAGE")
+		for _, e := range providers {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Key(), formatSize(e.Size), formatAge(time.Since(e.ModTime)))
+		}
+	}
+	return tw.Flush()
+}
+
+func runCacheShow(cmd *cobra.Command, args []string) error {
+	if strings.Contains(args[0], "@") {
+		return showProviderCacheEntry(cmd.OutOrStdout(), args[0])
+	}
+
+	entry, err := findCacheEntryArg(args[0])
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	key := entry.Key
+	if key == "" {
+		key = "-"
+	}
+	fmt.Fprintf(tw, "Key:\t%s\n", key)
+	fmt.Fprintf(tw, "File:\t%s\n", entry.Path)
+	fmt.Fprintf(tw, "Size:\t%s\n", formatSize(entry.Size))
+	fmt.Fprintf(tw, "Created:\t%s\n", entry.CreatedAt().Local().Format(time.RFC3339))
+	if meta := entry.Metadata; meta != nil {
+		fmt.Fprintf(tw, "Source:\t%s\n", meta.SourceDir)
+		if meta.DataDir != "" {
+			fmt.Fprintf(tw, "Data dir:\t%s\n", meta.DataDir)
+		}
+		fmt.Fprintf(tw, "Tool:\t%s\n", strings.TrimSpace(meta.Tool+" "+meta.ToolVersion))
+		if meta.Binary != "" {
+			fmt.Fprintf(tw, "Binary:\t%s\n", meta.Binary)
+		}
+	} else {
+		fmt.Fprintf(tw, "Source:\t(unknown, written before metadata was recorded)\n")
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	schemaWithVersion, err := terraform.ReadProviderSchemaFile(entry.Path)
+	if err != nil {
+		return err
+	}
+	var versions map[string]string
+	if entry.Metadata != nil {
+		versions = entry.Metadata.Providers
+	}
+	return writeCachedProviders(out, versions, schemaWithVersion)
+}
+
+// showProviderCacheEntry prints the header and contents of a per-provider entry
+func showProviderCacheEntry(out io.Writer, key string) error {
+	entry, err := terraform.FindProviderCacheEntry(key)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Key:\t%s\n", entry.Key())
+	fmt.Fprintf(tw, "File:\t%s\n", entry.Path)
+	fmt.Fprintf(tw, "Size:\t%s\n", formatSize(entry.Size))
+	if header, err := entry.ReadHeader(); err == nil {
+		fmt.Fprintf(tw, "Created:\t%s\n", header.CreatedAt.Local().Format(time.RFC3339))
+		fmt.Fprintf(tw, "Format:\t%s, %s\n", header.Format, header.Encoding)
+		fmt.Fprintf(tw, "Payload:\t%s, sha256 %s\n", formatSize(header.Size), header.Checksum)
+	} else {
+		fmt.Fprintf(tw, "Created:\t%s\n", entry.ModTime.Local().Format(time.RFC3339))
+		fmt.Fprintf(tw, "Format:\t(legacy, migrated when next read)\n")
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	schemaWithVersion, err := terraform.ReadProviderSchemaFile(entry.Path)
+	if err != nil {
+		return err
+	}
+	return writeCachedProviders(out, map[string]string{entry.Source: entry.Version}, schemaWithVersion)
+}
+
+// writeCachedProviders lists the providers in a cached schema with their versions, when
+// known, and entity counts
+func writeCachedProviders(w io.Writer, versions map[string]string, schemaWithVersion *terraform.SchemaWithVersionInfo) error {
+	if schemaWithVersion.Schemas == nil || len(schemaWithVersion.Schemas.Schemas) == 0 {
+		_, err := fmt.Fprintln(w, "\nNo providers")
+		return err
+	}
+
+	names := make([]string, 0, len(schemaWithVersion.Schemas.Schemas))
+	for name := range schemaWithVersion.Schemas.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "\nProviders:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		version := "-"
+		if versions[name] != "" {
+			version = versions[name]
+		}
+		ps := schemaWithVersion.Schemas.Schemas[name]
+		fmt.Fprintf(tw, "  %s\t%s\t%d resources, %d data sources, %d ephemeral, %d functions\n",
+			name, version,
+			len(ps.ResourceSchemas), len(ps.DataSourceSchemas), len(ps.EphemeralResourceSchemas), len(ps.Functions))
+	}
+	return tw.Flush()
+}
+
+func runCacheRm(cmd *cobra.Command, args []string) error {
+	for _, key := range args {
+		if strings.Contains(key, "@") {
+			entry, err := terraform.FindProviderCacheEntry(key)
+			if err != nil {
+				return err
+			}
+			if err := terraform.RemoveProviderCacheEntry(entry); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", entry.Key())
+			continue
+		}
+
+		entry, err := findCacheEntryArg(key)
+		if err != nil {
+			return err
+		}
+		if err := terraform.RemoveCacheEntry(entry); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", shortKey(entry.Key))
+	}
+	return nil
+}
+
+func runCachePrune(cmd *cobra.Command, args []string) error {
+	age, err := parseAge(cacheOlderThan)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-age)
+	removed, err := terraform.PruneCache(cutoff)
+	if err != nil {
+		return reportRemoved(cmd.OutOrStdout(), removed, nil, err)
+	}
+	providers, err := terraform.PruneProviderCache(cutoff)
+	return reportRemoved(cmd.OutOrStdout(), removed, providers, err)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	removed, err := terraform.ClearCache()
+	if err != nil {
+		return reportRemoved(cmd.OutOrStdout(), removed, nil, err)
+	}
+	providers, err := terraform.ClearProviderCache()
+	return reportRemoved(cmd.OutOrStdout(), removed, providers, err)
+}
+
+func reportRemoved(w io.Writer, removed []terraform.CacheEntry, providers []terraform.ProviderCacheEntry, err error) error {
+	var freed int64
+	for _, e := range removed {
+		freed += e.Size
+	}
+	for _, e := range providers {
+		freed += e.Size
+	}
+	fmt.Fprintf(w, "Removed %d cached schema(s) and %d provider schema(s), freed %s\n", len(removed), len(providers), formatSize(freed))
+	return err
+}
+
+// parseAge parses a duration, additionally accepting day (d) and week (w) suffixes
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (expected e.g. 12h, 30d or 2w)", s)
+	}
+	return d, nil
+}
+
+// findCacheEntryArg resolves a key argument; "-" selects the entry with an empty key,
+// which is written for directories without a lockfile or required_providers
+func findCacheEntryArg(key string) (terraform.CacheEntry, error) {
+	if key == "-" {
+		key = ""
+	}
+	return terraform.FindCacheEntry(key)
+}
+
+func shortKey(key string) string {
+	if key == "" {
+		return "-"
+	}
+	if len(key) > 12 {
+		return key[:12]
+	}
+	return key
+}
+
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+func TestCacheShow_ProviderEntry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	bin := filepath.Join(t.TempDir(), "terraform")
+	require.NoError(t, os.WriteFile(bin, []byte(`#!/bin/sh
+case "$1" in
+version) echo '{"terraform_version":"1.10.5"}' ;;
+providers) echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/aws":{"resource_schemas":{"aws_s3_bucket":{"version":0,"block":{}}}}}}' ;;
+esac
+`), 0755))
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), []byte("provider \"registry.terraform.io/hashicorp/aws\" {\n  version = \"6.0.0\"\n}\n"), 0644))
+	_, err := terraform.FetchAllProviderSchemas(dir, terraform.TerraformInfo{Binary: bin, Tool: "terraform"})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"cache", "show", "hashicorp/aws@6.0.0"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	require.NoError(t, rootCmd.Execute())
+
+	require.Contains(t, out.String(), "registry.terraform.io/hashicorp/aws@6.0.0")
+	require.Contains(t, out.String(), "Format:")
+	require.Regexp(t, `registry.terraform.io/hashicorp/aws\s+6.0.0\s+1 resources`, out.String())
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+var (
+	linkProvider   string
+	linkResource   string
+	linkDataSource string
+	linkEphemeral  string
+	linkPath       string
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&linkProvider, "provider", "", "open directly on this provider (aws, hashicorp/aws or a full address)")
+	rootCmd.Flags().StringVar(&linkResource, "resource", "", "open directly on this resource")
+	rootCmd.Flags().StringVar(&linkDataSource, "data-source", "", "open directly on this data source")
+	rootCmd.Flags().StringVar(&linkEphemeral, "ephemeral", "", "open directly on this ephemeral resource")
+	rootCmd.Flags().StringVar(&linkPath, "path", "", "place the tree cursor on this dotted attribute path (e.g. root_block_device.volume_size)")
+	rootCmd.MarkFlagsMutuallyExclusive("resource", "data-source", "ephemeral")
+}
+
+// deepLinkFromFlags builds the launch target from the deep-link flags, or nil when none are set
+func deepLinkFromFlags() (*ui.DeepLink, error) {
+	link := &ui.DeepLink{Provider: linkProvider, Path: linkPath}
+	switch {
+	case linkResource != "":
+		link.Type, link.Entity = ui.ResourcesType, linkResource
+	case linkDataSource != "":
+		link.Type, link.Entity = ui.DataSourcesType, linkDataSource
+	case linkEphemeral != "":
+		link.Type, link.Entity = ui.EphemeralResourcesType, linkEphemeral
+	}
+
+	if link.Path != "" && link.Entity == "" {
+		return nil, fmt.Errorf("--path requires --resource, --data-source or --ephemeral")
+	}
+	if link.Provider == "" && link.Entity == "" {
+		return nil, nil
+	}
+	return link, nil
+}
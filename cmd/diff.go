@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+var (
+	diffFormat         string
+	diffProvider       string
+	diffFailOnBreaking bool
+)
+
+// errBreakingChanges is returned by diff --fail-on-breaking when the report lists breaking
+// changes. The report already says so, so Execute exits with status 1 printing nothing more.
+var errBreakingChanges = errors.New("breaking changes found")
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare two provider schemas and classify breaking changes",
+	Long: `Compare two provider schema snapshots and report breaking changes, non-breaking
+changes and deprecations.
+
+Each side may be a Terraform working directory, a cache file from ~/.resource-cache
+or a document saved with ` + "`terraform providers schema -json`" + `.`,
+	Example: `  provider-explorer diff aws-5.json aws-6.json
+  provider-explorer diff ./envs/old ./envs/new --format markdown
+  provider-explorer diff old.json new.json --provider aws --format json --fail-on-breaking`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "output format: text, markdown or json")
+	diffCmd.Flags().StringVarP(&diffProvider, "provider", "p", "", "only compare this provider")
+	diffCmd.Flags().BoolVar(&diffFailOnBreaking, "fail-on-breaking", false, "exit with status 1 when breaking changes are found")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	switch diffFormat {
+	case "text", "markdown", "json":
+	default:
+		return fmt.Errorf("unknown format %q (expected text, markdown or json)", diffFormat)
+	}
+
+	oldSchemas, err := loadDiffSide(args[0])
+	if err != nil {
+		return err
+	}
+	newSchemas, err := loadDiffSide(args[1])
+	if err != nil {
+		return err
+	}
+
+	if diffProvider != "" {
+		if oldSchemas, err = filterProvider(oldSchemas, diffProvider); err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		if newSchemas, err = filterProvider(newSchemas, diffProvider); err != nil {
+			return fmt.Errorf("%s: %w", args[1], err)
+		}
+	}
+
+	report := schema.DiffProviderSchemas(oldSchemas, newSchemas)
+	out := cmd.OutOrStdout()
+	switch diffFormat {
+	case "json":
+		err = writeStructured(out, "json", report)
+	case "markdown":
+		err = schema.WriteDiffMarkdown(out, report)
+	default:
+		err = schema.WriteDiffText(out, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if diffFailOnBreaking && report.HasBreaking() {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return errBreakingChanges
+	}
+	return nil
+}
+
+// loadDiffSide loads schemas from a working directory or a schema file
+func loadDiffSide(path string) (*schema.ProviderSchemas, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		schemaWithVersion, err := terraform.ReadProviderSchemaFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return schemaWithVersion.Schemas, nil
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	tfInfo, err := resolveToolInfo()
+	if err != nil {
+		return nil, err
+	}
+	schemaWithVersion, err := terraform.FetchAllProviderSchemas(absPath, tfInfo)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return schemaWithVersion.Schemas, nil
+}
+
+// filterProvider narrows a schema document to a single provider.
+// The provider is keyed by the name given on the command line so documents from
+// different registries (e.g. terraform vs opentofu) still line up.
+func filterProvider(ps *schema.ProviderSchemas, name string) (*schema.ProviderSchemas, error) {
+	providerName, err := schema.ResolveProviderName(ps, name)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.ProviderSchemas{
+		FormatVersion: ps.FormatVersion,
+		Schemas:       map[string]*schema.ProviderSchema{name: ps.Schemas[providerName]},
+	}, nil
+}
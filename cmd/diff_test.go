@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_FailOnBreaking(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, attrs string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		doc := `{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{"attributes":{` + attrs + `}}}}}}}`
+		require.NoError(t, os.WriteFile(path, []byte(doc), 0644))
+		return path
+	}
+	oldPath := write("old.json", `"id":{"type":"string","computed":true},"triggers":{"type":["map","string"],"optional":true}`)
+	newPath := write("new.json", `"id":{"type":"string","computed":true}`)
+
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		var out, stderr bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&stderr)
+		rootCmd.SetArgs(args)
+		t.Cleanup(func() {
+			diffFailOnBreaking = false
+			rootCmd.SetArgs(nil)
+		})
+		err := rootCmd.Execute()
+		require.Empty(t, stderr.String(), "nothing is printed besides the report")
+		return out.String(), err
+	}
+
+	out, err := run("diff", oldPath, newPath)
+	require.NoError(t, err)
+	require.Contains(t, out, "triggers")
+
+	out, err = run("diff", oldPath, newPath, "--fail-on-breaking")
+	require.True(t, errors.Is(err, errBreakingChanges), "got %v", err)
+	require.Contains(t, out, "triggers")
+
+	_, err = run("diff", oldPath, oldPath, "--fail-on-breaking")
+	require.NoError(t, err)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+var (
+	exportArgs     []string
+	exportOutputs  []string
+	exportInstance string
+	exportProvider string
+	exportKind     string
+	exportOutFile  string
+	exportDir      string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <name>",
+	Short: "Generate variable and output blocks for selected attribute paths",
+	Long: `Generate Terraform variable blocks for selected arguments and output blocks for
+selected computed attributes, without starting the TUI.
+
+Paths use the same dotted notation as the schema tree. Selecting a nested path
+implicitly selects its parent blocks, and selecting a block selects everything
+below it.`,
+	Example: `  provider-explorer export aws_instance --args ami,instance_type,root_block_device.volume_size
+  provider-explorer export aws_instance --outputs arn,id --instance main -o outputs.tf`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringSliceVar(&exportArgs, "args", nil, "argument paths to export as variables")
+	exportCmd.Flags().StringSliceVar(&exportOutputs, "outputs", nil, "computed attribute paths to export as outputs")
+	exportCmd.Flags().StringVar(&exportInstance, "instance", "main", "resource instance name used in output references")
+	exportCmd.Flags().StringVarP(&exportProvider, "provider", "p", "", "provider name (detected from the entity name when empty)")
+	exportCmd.Flags().StringVarP(&exportKind, "kind", "k", "resource", "entity kind: resource, data or ephemeral")
+	exportCmd.Flags().StringVarP(&exportOutFile, "out", "o", "", "write the generated HCL to a file instead of stdout")
+	exportCmd.Flags().StringVarP(&exportDir, "dir", "C", ".", "Terraform working directory")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if len(exportArgs) == 0 && len(exportOutputs) == 0 {
+		return fmt.Errorf("nothing to export: use --args and/or --outputs")
+	}
+
+	kind, err := schema.ParseEntityKind(exportKind)
+	if err != nil {
+		return err
+	}
+	if kind == schema.KindFunction {
+		return fmt.Errorf("functions cannot be exported")
+	}
+
+	absPath, err := filepath.Abs(exportDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	tfInfo, err := resolveToolInfo()
+	if err != nil {
+		return err
+	}
+
+	schemaWithVersion, err := terraform.FetchAllProviderSchemas(absPath, tfInfo)
+	if err != nil {
+		return err
+	}
+
+	entityName := args[0]
+	providerName, err := findEntityProvider(schemaWithVersion.Schemas, exportProvider, kind, entityName)
+	if err != nil {
+		return err
+	}
+
+	entitySchema, err := schema.GetEntitySchema(schemaWithVersion.Schemas, providerName, kind, entityName)
+	if err != nil {
+		return err
+	}
+	if entitySchema.Block == nil {
+		return fmt.Errorf("%s has no block schema", entityName)
+	}
+
+	var b strings.Builder
+	if len(exportArgs) > 0 {
+		paths, err := ui.ResolveSelectedPaths(entitySchema.Block, exportArgs, ui.ArgumentsMode)
+		if err != nil {
+			return fmt.Errorf("--args: %w", err)
+		}
+		b.WriteString(ui.ConvertSelectedArgumentsToHCLVariables(entitySchema, paths))
+	}
+	if len(exportOutputs) > 0 {
+		paths, err := ui.ResolveSelectedPaths(entitySchema.Block, exportOutputs, ui.AttributesMode)
+		if err != nil {
+			return fmt.Errorf("--outputs: %w", err)
+		}
+		b.WriteString(ui.ConvertSelectedAttributesToHCLOutputs(entityName, entitySchema, providerName, exportInstance, paths))
+	}
+
+	if exportOutFile == "" {
+		_, err = fmt.Fprint(cmd.OutOrStdout(), b.String())
+		return err
+	}
+	if err := os.WriteFile(exportOutFile, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", exportOutFile, err)
+	}
+	return nil
+}
+
+// findEntityProvider resolves the provider owning an entity. When no provider is given,
+// every provider is searched and the entity name must be unambiguous.
+func findEntityProvider(providerSchemas *schema.ProviderSchemas, provider string, kind schema.EntityKind, entityName string) (string, error) {
+	if provider != "" {
+		return schema.ResolveProviderName(providerSchemas, provider)
+	}
+
+	var matches []string
+	for name := range providerSchemas.Schemas {
+		if _, err := schema.GetEntitySchema(providerSchemas, name, kind, entityName); err == nil {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%s %s not found in any provider", kind, entityName)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%s %s is provided by several providers (%s), use --provider", kind, entityName, strings.Join(matches, ", "))
+	}
+}
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -23,14 +25,77 @@ through providers, resource types, and detailed resource schemas.`,
 	RunE: runTUI,
 }
 
+var (
+	schemaFile string
+	bundleFile string
+	assumeYes  bool
+	noInit     bool
+	watch      bool
+	fromPlugin bool
+	pluginDirs []string
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&schemaFile, "schema-file", "", "open a saved 'providers schema -json' document instead of a Terraform directory (use - for stdin)")
+	rootCmd.Flags().StringVar(&bundleFile, "bundle", "", "open a bundle written by 'pack' instead of a Terraform directory")
+	rootCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "run init without asking when the workspace is not initialized")
+	rootCmd.Flags().BoolVar(&noInit, "no-init", false, "fail instead of running init when the workspace is not initialized")
+	rootCmd.Flags().BoolVar(&watch, "watch", false, "reload schemas when the lockfile, installed providers or *.tf files change")
+	rootCmd.Flags().BoolVar(&fromPlugin, "from-plugins", false, "read schemas straight from the installed provider binaries instead of running 'providers schema -json'")
+	rootCmd.Flags().StringArrayVar(&pluginDirs, "plugin-dir", nil, "additional plugin cache directory to search with --from-plugins (repeatable)")
+	rootCmd.MarkFlagsMutuallyExclusive("yes", "no-init")
+	rootCmd.MarkFlagsMutuallyExclusive("schema-file", "watch")
+	rootCmd.MarkFlagsMutuallyExclusive("schema-file", "from-plugins")
+	rootCmd.MarkFlagsMutuallyExclusive("from-plugins", "watch")
+	rootCmd.MarkFlagsMutuallyExclusive("bundle", "schema-file")
+	rootCmd.MarkFlagsMutuallyExclusive("bundle", "from-plugins")
+	rootCmd.MarkFlagsMutuallyExclusive("bundle", "watch")
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if !errors.Is(err, errBreakingChanges) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
 		os.Exit(1)
 	}
 }
 
 func runTUI(cmd *cobra.Command, args []string) error {
+	tfInfo, err := resolveToolInfo()
+	if err != nil {
+		return err
+	}
+
+	link, err := deepLinkFromFlags()
+	if err != nil {
+		return err
+	}
+
+	if schemaFile != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--schema-file cannot be combined with a directory argument")
+		}
+		return runSchemaFileTUI(schemaFile, tfInfo, link)
+	}
+
+	if bundleFile != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--bundle cannot be combined with a directory argument")
+		}
+		return runBundleTUI(bundleFile, tfInfo, link)
+	}
+
+	if providerSource != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--provider-source cannot be combined with a directory argument")
+		}
+		return runProviderSourceTUI(tfInfo, link)
+	}
+	if providerVersion != "" {
+		return fmt.Errorf("--version requires --provider-source")
+	}
+
 	workingDir := "."
 	if len(args) > 0 {
 		workingDir = args[0]
@@ -42,15 +107,31 @@ func runTUI(cmd *cobra.Command, args []string) error {
 	}
 
 	if !config.HasTerraformConfig(absPath) {
-		return fmt.Errorf("no Terraform configuration found in %s", absPath)
+		workspaces, err := config.FindRootModules(absPath)
+		if err != nil {
+			return fmt.Errorf("failed to search for root modules: %w", err)
+		}
+		switch {
+		case len(workspaces) == 0:
+			return fmt.Errorf("no Terraform configuration found in %s", absPath)
+		case len(workspaces) == 1:
+			absPath = filepath.Join(absPath, filepath.FromSlash(workspaces[0]))
+		case fromPlugin || watch:
+			return fmt.Errorf("%s holds %d root modules (%s); pass one of them to use --from-plugins or --watch",
+				absPath, len(workspaces), strings.Join(workspaces, ", "))
+		default:
+			return runWorkspacePickerTUI(absPath, workspaces, tfInfo, link)
+		}
 	}
 
-	// Check if we have a valid cache first
-	if !terraform.HasValidProviderCache(absPath) {
-		// Only prompt for init if no valid cache exists
-		if err := config.InitTerraformDirectory(absPath); err != nil {
-			return err
-		}
+	if fromPlugin {
+		return runPluginTUI(cmd, absPath, tfInfo, link)
+	}
+
+	// Init is only needed when nothing is cached and the workspace was never initialized
+	needsInit := !terraform.HasValidProviderCache(absPath, tfInfo) && config.NeedsInit(absPath)
+	if needsInit && noInit {
+		return fmt.Errorf("%s is not initialized; run '%s init' first or drop --no-init", absPath, tfInfo.Tool)
 	}
 
 	// Change to the working directory so the UI can load schemas
@@ -60,6 +141,16 @@ func runTUI(cmd *cobra.Command, args []string) error {
 
 	// Create model with default terminal size (will be updated by tea.WindowSizeMsg)
 	model := ui.NewModel(80, 24)
+	model.SetToolInfo(tfInfo)
+	if needsInit {
+		model.SetInitRequired(assumeYes)
+	}
+	if link != nil {
+		model.SetDeepLink(*link)
+	}
+	if watch {
+		model.EnableWatch(cmd.Context(), terraform.DefaultWatchInterval)
+	}
 
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
@@ -69,3 +160,82 @@ func runTUI(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runPluginTUI starts the explorer on schemas read straight from the provider plugins
+// installed for a working directory, so neither init's schema step nor a working
+// 'providers schema' is needed once the binaries are present.
+func runPluginTUI(cmd *cobra.Command, absPath string, tfInfo terraform.TerraformInfo, link *ui.DeepLink) error {
+	dirs := make([]string, len(pluginDirs))
+	for i, dir := range pluginDirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve plugin dir: %w", err)
+		}
+		dirs[i] = abs
+	}
+
+	if err := os.Chdir(absPath); err != nil {
+		return fmt.Errorf("failed to change directory: %w", err)
+	}
+
+	ctx := cmd.Context()
+	model := ui.NewModelFromLoader(func() (*terraform.SchemaWithVersionInfo, error) {
+		return terraform.FetchProviderSchemasFromPlugins(ctx, ".", tfInfo, dirs)
+	}, 80, 24)
+	if link != nil {
+		model.SetDeepLink(*link)
+	}
+	return runProgram(model)
+}
+
+// runWorkspacePickerTUI starts the explorer on a picker over the root modules of a
+// monorepo. Each picked workspace is initialized and loaded like a directory argument.
+func runWorkspacePickerTUI(root string, workspaces []string, tfInfo terraform.TerraformInfo, link *ui.DeepLink) error {
+	if noInit {
+		return fmt.Errorf("%s holds %d root modules; pass one of them to use --no-init", root, len(workspaces))
+	}
+
+	model := ui.NewModel(80, 24)
+	model.SetToolInfo(tfInfo)
+	model.SetWorkspaces(root, workspaces, assumeYes)
+	if link != nil {
+		model.SetDeepLink(*link)
+	}
+	return runProgram(model)
+}
+
+// runSchemaFileTUI starts the explorer on a saved schema document. No Terraform
+// configuration, init or credentials are needed; the local tool is only consulted
+// for its version so feature gating matches what is installed.
+func runSchemaFileTUI(path string, tfInfo terraform.TerraformInfo, link *ui.DeepLink) error {
+	schemas, err := ui.LoadProvidersSchemaFromFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load schema file: %w", err)
+	}
+	if len(schemas.Schemas) == 0 {
+		return fmt.Errorf("no provider schemas found in %s", path)
+	}
+
+	var version string
+	if versionInfo, err := terraform.DetectVersion(tfInfo, "."); err == nil {
+		version = versionInfo.Version
+	}
+
+	model := ui.NewModelFromSchemas(schemas, tfInfo, version, 80, 24)
+	if link != nil {
+		model.SetDeepLink(*link)
+	}
+
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if path == "-" {
+		// stdin carried the schema document, read keys from the terminal instead
+		opts = append(opts, tea.WithInputTTY())
+	}
+
+	p := tea.NewProgram(model, opts...)
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("failed to start TUI: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+var (
+	schemaKind   string
+	schemaFormat string
+	schemaDir    string
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema <provider> <name>",
+	Short: "Print the schema of a single resource, data source or function",
+	Long: `Print the schema of a single provider entity without starting the TUI.
+
+The provider may be given as a short name (aws), a namespaced name (hashicorp/aws)
+or a full address (registry.terraform.io/hashicorp/aws). Schemas are read from the
+same cache used by the interactive explorer.`,
+	Example: `  provider-explorer schema aws aws_instance
+  provider-explorer schema aws aws_ami --kind data --format yaml
+  provider-explorer schema aws arn_parse --kind function --format json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSchema,
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaKind, "kind", "k", "resource", "entity kind: resource, data, ephemeral or function")
+	schemaCmd.Flags().StringVarP(&schemaFormat, "format", "f", "tree", "output format: json, yaml or tree")
+	schemaCmd.Flags().StringVarP(&schemaDir, "dir", "C", ".", "Terraform working directory")
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	kind, err := schema.ParseEntityKind(schemaKind)
+	if err != nil {
+		return err
+	}
+
+	switch schemaFormat {
+	case "json", "yaml", "tree":
+	default:
+		return fmt.Errorf("unknown format %q (expected json, yaml or tree)", schemaFormat)
+	}
+
+	absPath, err := filepath.Abs(schemaDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	tfInfo, err := resolveToolInfo()
+	if err != nil {
+		return err
+	}
+
+	schemaWithVersion, err := terraform.FetchAllProviderSchemas(absPath, tfInfo)
+	if err != nil {
+		return err
+	}
+
+	providerName, err := schema.ResolveProviderName(schemaWithVersion.Schemas, args[0])
+	if err != nil {
+		return err
+	}
+	entityName := args[1]
+	out := cmd.OutOrStdout()
+
+	if kind == schema.KindFunction {
+		fn, err := schema.GetFunctionSchema(schemaWithVersion.Schemas, providerName, entityName)
+		if err != nil {
+			return err
+		}
+		if schemaFormat == "tree" {
+			return schema.WriteFunctionSignature(out, entityName, fn)
+		}
+		return writeStructured(out, schemaFormat, fn)
+	}
+
+	entitySchema, err := schema.GetEntitySchema(schemaWithVersion.Schemas, providerName, kind, entityName)
+	if err != nil {
+		return err
+	}
+	if schemaFormat == "tree" {
+		return schema.WriteSchemaTree(out, entityName, entitySchema)
+	}
+	return writeStructured(out, schemaFormat, entitySchema)
+}
+
+// writeStructured encodes v as indented JSON or as YAML.
+// YAML is produced from the JSON encoding so field names match `providers schema -json`.
+func writeStructured(w io.Writer, format string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	if format == "json" {
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return fmt.Errorf("failed to convert schema: %w", err)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return fmt.Errorf("failed to encode yaml: %w", err)
+	}
+	return enc.Close()
+}
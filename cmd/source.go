@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+var (
+	providerSource  string
+	providerVersion string
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&providerSource, "provider-source", "", "explore this provider (e.g. hashicorp/aws) without any Terraform configuration")
+	rootCmd.Flags().StringVar(&providerVersion, "version", "", "version constraint for --provider-source (e.g. \"~> 6.0\"; default: latest)")
+	rootCmd.MarkFlagsMutuallyExclusive("provider-source", "schema-file")
+	rootCmd.MarkFlagsMutuallyExclusive("provider-source", "from-plugins")
+	rootCmd.MarkFlagsMutuallyExclusive("provider-source", "watch")
+	rootCmd.MarkFlagsMutuallyExclusive("provider-source", "no-init")
+	rootCmd.MarkFlagsMutuallyExclusive("provider-source", "bundle")
+}
+
+// runProviderSourceTUI explores a provider that no configuration requires yet. It
+// synthesises a throwaway workspace requiring just that provider, initializes it inside
+// the TUI (from --mirror when given) and loads its schema, which lands in the
+// per-provider cache as usual. A pinned version that was fetched before opens straight
+// from the cache.
+func runProviderSourceTUI(tfInfo terraform.TerraformInfo, link *ui.DeepLink) error {
+	source, err := terraform.ProviderSourceAddress(providerSource, tfInfo)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		link = &ui.DeepLink{}
+	}
+	if link.Provider == "" {
+		link.Provider = source
+	}
+
+	if version, ok := terraform.ExactVersion(providerVersion); ok {
+		if cached, err := terraform.ReadCachedProviderSchema(source, version, tfInfo); err == nil {
+			model := ui.NewModelFromLoader(func() (*terraform.SchemaWithVersionInfo, error) {
+				return cached, nil
+			}, 80, 24)
+			model.SetDeepLink(*link)
+			return runProgram(model)
+		}
+	}
+
+	dir, err := terraform.CreateScratchWorkspace(providerSource, providerVersion)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("failed to change directory: %w", err)
+	}
+
+	model := ui.NewModel(80, 24)
+	model.SetToolInfo(tfInfo)
+	model.SetInitRequired(true)
+	model.SetDeepLink(*link)
+	return runProgram(model)
+}
+
+// runProgram runs the TUI until the user quits
+func runProgram(model tea.Model, opts ...tea.ProgramOption) error {
+	p := tea.NewProgram(model, append([]tea.ProgramOption{tea.WithAltScreen()}, opts...)...)
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("failed to start TUI: %w", err)
+	}
+	return nil
+}
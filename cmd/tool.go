@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+// Environment variables used when --tool/--binary are not given
+const (
+	toolEnvVar   = "PROVIDER_EXPLORER_TOOL"
+	binaryEnvVar = "PROVIDER_EXPLORER_BINARY"
+)
+
+var (
+	toolFlag       string
+	binaryFlag     string
+	mirrorFlag     string
+	pluginCacheDir string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&toolFlag, "tool", "", "tool to run: terraform or tofu (env "+toolEnvVar+")")
+	rootCmd.PersistentFlags().StringVar(&binaryFlag, "binary", "", "explicit path to the terraform or tofu binary (env "+binaryEnvVar+")")
+	rootCmd.PersistentFlags().StringVar(&mirrorFlag, "mirror", "", "install providers only from this local filesystem mirror (fully offline)")
+	rootCmd.PersistentFlags().StringVar(&pluginCacheDir, "plugin-cache-dir", "", "plugin cache directory for init (overrides TF_PLUGIN_CACHE_DIR)")
+}
+
+// resolveToolInfo picks the terraform/tofu binary from flags, then environment, then auto-detection,
+// and applies the provider installation flags
+func resolveToolInfo() (terraform.TerraformInfo, error) {
+	tool := toolFlag
+	if tool == "" {
+		tool = os.Getenv(toolEnvVar)
+	}
+	binary := binaryFlag
+	if binary == "" {
+		binary = os.Getenv(binaryEnvVar)
+	}
+	tfInfo, err := terraform.ResolveTerraformInfo(tool, binary)
+	if err != nil {
+		return tfInfo, err
+	}
+
+	for _, dir := range []struct {
+		flag string
+		path *string
+	}{
+		{"--mirror", &mirrorFlag},
+		{"--plugin-cache-dir", &pluginCacheDir},
+	} {
+		if *dir.path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*dir.path)
+		if err != nil {
+			return tfInfo, fmt.Errorf("failed to resolve %s: %w", dir.flag, err)
+		}
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			return tfInfo, fmt.Errorf("%s %s is not a directory", dir.flag, abs)
+		}
+		*dir.path = abs
+	}
+	tfInfo.Installation = terraform.ProviderInstallation{
+		MirrorDir:      mirrorFlag,
+		PluginCacheDir: pluginCacheDir,
+	}
+	return tfInfo, nil
+}
@@ -1,68 +1,36 @@
 package config
 
 import (
-	"bufio"
-	"fmt"
-	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 
 	"github.com/terraconstructs/provider-explorer/internal/terraform"
 )
 
+// HasTerraformConfig reports whether dir itself holds Terraform configuration files.
+// Subdirectories, such as the modules downloaded into .terraform, are not considered;
+// use FindRootModules to discover the root modules of a monorepo.
 func HasTerraformConfig(dir string) bool {
-	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if d.IsDir() {
-			return nil
-		}
-
-		if strings.HasSuffix(d.Name(), ".tf") ||
-			(strings.HasSuffix(d.Name(), ".json") && strings.Contains(d.Name(), ".tf.")) {
-			return fmt.Errorf("found") // Use error to break out of walk
-		}
-
-		return nil
-	})
-
-	return err != nil && err.Error() == "found"
-}
-
-func InitTerraformDirectory(dir string) error {
-	tfInfo := terraform.FindTerraformBinary()
-
-	fmt.Printf("Terraform configuration detected in %s\n", dir)
-	fmt.Printf("This will run '%s init' to download providers.\n", tfInfo.Binary)
-	fmt.Print("Continue? [y/N]: ")
-
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("failed to read user input: %w", err)
+		return false
 	}
-
-	response = strings.TrimSpace(strings.ToLower(response))
-	if response != "y" && response != "yes" {
-		return fmt.Errorf("terraform init cancelled by user")
+	for _, entry := range entries {
+		if !entry.IsDir() && isConfigFile(entry.Name()) {
+			return true
+		}
 	}
+	return false
+}
 
-	fmt.Printf("\nRunning %s init...\n", tfInfo.Binary)
-
-	cmd := exec.Command(tfInfo.Binary, "init")
-	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("terraform init failed: %w", err)
+// NeedsInit reports whether the working directory has not been initialized yet:
+// either the dependency lock file or the installed providers directory is missing.
+// TF_DATA_DIR is honoured when set.
+func NeedsInit(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, ".terraform.lock.hcl")); err != nil {
+		return true
 	}
 
-	fmt.Printf("\n%s init completed successfully!\n", tfInfo.Binary)
-	return nil
+	_, err := os.Stat(filepath.Join(terraform.DataDir(dir), "providers"))
+	return err != nil
 }
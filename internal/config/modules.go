@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+// Module is a module called by a workspace, with the interface it offers to its caller
+type Module struct {
+	// Key is the module's address below the root module without the module. prefixes,
+	// e.g. vpc, or vpc.subnets for a module called by the vpc module
+	Key     string
+	Source  string
+	Version string // installed version, or the version constraint when not installed
+	Dir     string // empty when the module is not installed
+
+	Variables []*tfconfig.Variable // sorted by name
+	Outputs   []*tfconfig.Output   // sorted by name
+
+	// Err records why the variables and outputs could not be read, e.g. a module that
+	// was never installed
+	Err error
+}
+
+// Name returns the last segment of the module key, the name it is called by
+func (m Module) Name() string {
+	return m.Key[strings.LastIndex(m.Key, ".")+1:]
+}
+
+// RootCall reports whether the module is called by the root module, the only modules
+// module.<name> refers to from there
+func (m Module) RootCall() bool {
+	return !strings.Contains(m.Key, ".")
+}
+
+// LoadModules returns the modules of the working directory sorted by key: the module
+// calls of its configuration, together with every module installed under the data
+// directory, including the ones called by other modules. Modules that are called but
+// not installed are only readable when their source is a local path.
+func LoadModules(workingDir string) ([]Module, error) {
+	root, diags := tfconfig.LoadModule(workingDir)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to load terraform module: %v", diags)
+	}
+
+	modules := make(map[string]*Module)
+	for name, call := range root.ModuleCalls {
+		mod := &Module{Key: name, Source: call.Source, Version: call.Version}
+		if terraform.IsLocalModuleSource(call.Source) {
+			mod.Dir = filepath.Join(workingDir, filepath.FromSlash(call.Source))
+		}
+		modules[name] = mod
+	}
+
+	installed, err := terraform.ReadModuleManifest(workingDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range installed {
+		if entry.Key == "" {
+			// The root module itself
+			continue
+		}
+		mod, ok := modules[entry.Key]
+		if !ok {
+			mod = &Module{Key: entry.Key, Source: entry.Source}
+			modules[entry.Key] = mod
+		}
+		if entry.Version != "" {
+			mod.Version = entry.Version
+		}
+		mod.Dir = filepath.Join(workingDir, filepath.FromSlash(entry.Dir))
+	}
+
+	result := make([]Module, 0, len(modules))
+	for _, mod := range modules {
+		mod.load()
+		result = append(result, *mod)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// load reads the variables and outputs of an installed module
+func (m *Module) load() {
+	if m.Dir == "" {
+		m.Err = errors.New("module is not installed; run init")
+		return
+	}
+	if _, err := os.Stat(m.Dir); err != nil {
+		m.Err = fmt.Errorf("module is not installed; run init: %w", err)
+		return
+	}
+
+	module, diags := tfconfig.LoadModule(m.Dir)
+	if diags.HasErrors() {
+		m.Err = diags.Err()
+	}
+	for _, v := range module.Variables {
+		m.Variables = append(m.Variables, v)
+	}
+	sort.Slice(m.Variables, func(i, j int) bool {
+		return m.Variables[i].Name < m.Variables[j].Name
+	})
+	for _, o := range module.Outputs {
+		m.Outputs = append(m.Outputs, o)
+	}
+	sort.Slice(m.Outputs, func(i, j int) bool {
+		return m.Outputs[i].Name < m.Outputs[j].Name
+	})
+}
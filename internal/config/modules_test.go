@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadModules(t *testing.T) {
+	t.Setenv("TF_DATA_DIR", "")
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write("main.tf", `module "app" {
+  source = "./modules/app"
+}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 5.0"
+}
+
+module "dns" {
+  source = "git::https://example.com/dns.git"
+}
+`)
+	write("modules/app/variables.tf", `variable "name" {
+  type        = string
+  description = "Name of the app"
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
+
+variable "token" {
+  sensitive = true
+  default   = null
+}
+`)
+	write("modules/app/outputs.tf", `output "url" {
+  value = "https://example.com"
+}
+`)
+	write(".terraform/modules/vpc/main.tf", `variable "cidr" {
+  default = "10.0.0.0/16"
+}
+
+output "vpc_id" {
+  value     = "vpc-123"
+  sensitive = true
+}
+`)
+	write(".terraform/modules/vpc.subnets/main.tf", `variable "subnet_count" {
+  type = number
+}
+`)
+	write(".terraform/modules/modules.json", `{"Modules":[
+  {"Key":"","Source":"","Dir":"."},
+  {"Key":"vpc","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Version":"5.1.2","Dir":".terraform/modules/vpc"},
+  {"Key":"vpc.subnets","Source":"./modules/subnets","Dir":".terraform/modules/vpc.subnets"}
+]}`)
+
+	modules, err := LoadModules(root)
+	require.NoError(t, err)
+
+	var keys []string
+	for _, m := range modules {
+		keys = append(keys, m.Key)
+	}
+	require.Equal(t, []string{"app", "dns", "vpc", "vpc.subnets"}, keys)
+
+	app := modules[0]
+	require.NoError(t, app.Err)
+	require.Equal(t, "./modules/app", app.Source)
+	require.Len(t, app.Variables, 3)
+	require.Equal(t, "name", app.Variables[0].Name)
+	require.Equal(t, "string", app.Variables[0].Type)
+	require.True(t, app.Variables[0].Required)
+	require.False(t, app.Variables[1].Required)
+	require.True(t, app.Variables[2].Sensitive)
+	require.Len(t, app.Outputs, 1)
+
+	// Remote modules are readable once installed
+	dns := modules[1]
+	require.Error(t, dns.Err)
+	require.Empty(t, dns.Dir)
+
+	vpc := modules[2]
+	require.NoError(t, vpc.Err)
+	require.Equal(t, "terraform-aws-modules/vpc/aws", vpc.Source)
+	require.Equal(t, "5.1.2", vpc.Version)
+	require.Equal(t, "10.0.0.0/16", vpc.Variables[0].Default)
+	require.True(t, vpc.Outputs[0].Sensitive)
+
+	subnets := modules[3]
+	require.Equal(t, "subnets", subnets.Name())
+	require.Len(t, subnets.Variables, 1)
+}
@@ -12,8 +12,8 @@ type ProviderInfo struct {
 	Source  string
 }
 
-func GetInstalledProviders(dir string) ([]ProviderInfo, error) {
-	schemaWithVersion, err := terraform.FetchAllProviderSchemas(dir)
+func GetInstalledProviders(dir string, tfInfo terraform.TerraformInfo) ([]ProviderInfo, error) {
+	schemaWithVersion, err := terraform.FetchAllProviderSchemas(dir, tfInfo)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +31,6 @@ func GetInstalledProviders(dir string) ([]ProviderInfo, error) {
 			displayName = parts[len(parts)-1] // Just "aws"
 		} else if len(parts) == 2 {
 			// Format: hashicorp/aws
-			tfInfo := terraform.FindTerraformBinary()
 			source = tfInfo.Registry + "/" + name
 			displayName = parts[1] // Just "aws"
 		}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+// terraformBlockSchema matches the top-level terraform block of a configuration file
+var terraformBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{{Type: "terraform"}},
+}
+
+// isConfigFile reports whether name is a Terraform configuration file
+func isConfigFile(name string) bool {
+	return strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")
+}
+
+// FindRootModules returns the root modules below dir, as slash-separated paths relative
+// to dir ("." for dir itself), sorted. A root module is a directory with a dependency
+// lockfile or a configuration file declaring a terraform block. Hidden directories,
+// including .terraform with its downloaded modules, are not searched.
+func FindRootModules(dir string) ([]string, error) {
+	var modules []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if isRootModule(path) {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			modules = append(modules, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(modules)
+	return modules, nil
+}
+
+// isRootModule reports whether dir has a lockfile or a configuration file with a
+// terraform block
+func isRootModule(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, ".terraform.lock.hcl")); err == nil {
+		return true
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	parser := hclparse.NewParser()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !isConfigFile(name) {
+			continue
+		}
+		var file *hcl.File
+		if strings.HasSuffix(name, ".json") {
+			file, _ = parser.ParseJSONFile(filepath.Join(dir, name))
+		} else {
+			file, _ = parser.ParseHCLFile(filepath.Join(dir, name))
+		}
+		if file == nil {
+			continue
+		}
+		content, _, _ := file.Body.PartialContent(terraformBlockSchema)
+		if content != nil && len(content.Blocks) > 0 {
+			return true
+		}
+	}
+	return false
+}
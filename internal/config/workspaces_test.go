@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRootModules(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write("README.md", "# monorepo\n")
+	write("envs/prod/main.tf", "terraform {\n  required_version = \">= 1.5\"\n}\n")
+	write("envs/dev/main.tf", "module \"app\" {\n  source = \"../../modules/app\"\n}\n")
+	write("envs/dev/.terraform.lock.hcl", "")
+	write("envs/json/main.tf.json", `{"terraform": {"required_providers": {}}}`)
+	write("modules/app/main.tf", "resource \"null_resource\" \"this\" {}\n")
+	write("envs/prod/.terraform/modules/app/main.tf", "terraform {}\n")
+
+	modules, err := FindRootModules(root)
+	require.NoError(t, err)
+	require.Equal(t, []string{"envs/dev", "envs/json", "envs/prod"}, modules)
+
+	require.False(t, HasTerraformConfig(root))
+	require.True(t, HasTerraformConfig(filepath.Join(root, "envs", "prod")))
+	require.True(t, HasTerraformConfig(filepath.Join(root, "envs", "json")))
+}
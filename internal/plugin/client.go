@@ -0,0 +1,130 @@
+// Package plugin reads provider schemas straight from provider plugin binaries. It
+// launches a provider the way terraform and tofu do, using the go-plugin handshake over
+// gRPC, and asks it for its schema with the GetProviderSchema call of protocol 5 or 6.
+//
+// The tfplugin5 and tfplugin6 packages hold the protocol definitions and generated stubs,
+// copied from terraform-plugin-go as the protocol files recommend.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	tfjson "github.com/hashicorp/terraform-json"
+	"google.golang.org/grpc"
+
+	"github.com/terraconstructs/provider-explorer/internal/plugin/tfplugin5"
+	"github.com/terraconstructs/provider-explorer/internal/plugin/tfplugin6"
+)
+
+// Handshake is the go-plugin handshake terraform and tofu use for providers. The
+// protocol version is negotiated from VersionedPlugins instead.
+var Handshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "TF_PLUGIN_MAGIC_COOKIE",
+	MagicCookieValue: "d602bf8f470bc67ca7faa0386276bbdd4330efaf76d1a219cb4d6991ca9872b2",
+}
+
+// maxSchemaSize bounds a GetProviderSchema response. The largest providers send tens
+// of megabytes, far beyond gRPC's 4MB default.
+const maxSchemaSize = 256 << 20
+
+// GetProviderSchema launches the provider plugin at path, asks it for its schema and
+// stops it again. Both plugin protocol 5 and 6 are supported.
+func GetProviderSchema(ctx context.Context, path string) (*tfjson.ProviderSchema, error) {
+	var stderr lockedBuffer
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		VersionedPlugins: map[int]goplugin.PluginSet{
+			5: {"provider": grpcProvider{}},
+			6: {"provider": grpcProvider{}},
+		},
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		Cmd:              exec.CommandContext(ctx, path),
+		AutoMTLS:         true,
+		Logger:           hclog.NewNullLogger(),
+		Stderr:           &stderr,
+		GRPCDialOptions: []grpc.DialOption{
+			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxSchemaSize)),
+		},
+	})
+	defer client.Kill()
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		// Kill waits for the plugin's output to be copied
+		client.Kill()
+		return nil, launchError(path, err, stderr.String())
+	}
+	raw, err := rpcClient.Dispense("provider")
+	if err != nil {
+		return nil, launchError(path, err, stderr.String())
+	}
+	conn := raw.(*grpc.ClientConn)
+
+	switch version := client.NegotiatedVersion(); version {
+	case 5:
+		resp, err := tfplugin5.NewProviderClient(conn).GetSchema(ctx, &tfplugin5.GetProviderSchema_Request{})
+		if err != nil {
+			return nil, fmt.Errorf("GetSchema on %s: %w", path, err)
+		}
+		return convertV5(resp)
+	case 6:
+		resp, err := tfplugin6.NewProviderClient(conn).GetProviderSchema(ctx, &tfplugin6.GetProviderSchema_Request{})
+		if err != nil {
+			return nil, fmt.Errorf("GetProviderSchema on %s: %w", path, err)
+		}
+		return convertV6(resp)
+	default:
+		return nil, fmt.Errorf("%s speaks unsupported plugin protocol %d", path, version)
+	}
+}
+
+// launchError reports the first line of go-plugin's lengthy error together with the
+// last line the plugin wrote to stderr, which usually explains why it did not start
+func launchError(path string, err error, stderr string) error {
+	reason := strings.TrimSpace(strings.SplitN(err.Error(), "\n", 2)[0])
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		reason += " " + last
+	}
+	return fmt.Errorf("failed to start %s: %s", path, reason)
+}
+
+// lockedBuffer collects plugin stderr, which go-plugin copies from another goroutine
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// grpcProvider hands out the raw connection; the caller picks the client stub matching
+// the negotiated protocol version
+type grpcProvider struct {
+	goplugin.NetRPCUnsupportedPlugin
+}
+
+func (grpcProvider) GRPCClient(_ context.Context, _ *goplugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return conn, nil
+}
+
+func (grpcProvider) GRPCServer(*goplugin.GRPCBroker, *grpc.Server) error {
+	return errors.New("provider plugins are only used as clients")
+}
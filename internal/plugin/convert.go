@@ -0,0 +1,275 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/terraconstructs/provider-explorer/internal/plugin/tfplugin5"
+	"github.com/terraconstructs/provider-explorer/internal/plugin/tfplugin6"
+)
+
+// The converters below produce what `providers schema -json` prints for the same
+// response, so schemas read from plugins and from the tool are interchangeable.
+
+func convertV6(resp *tfplugin6.GetProviderSchema_Response) (*tfjson.ProviderSchema, error) {
+	var errs []string
+	for _, diag := range resp.GetDiagnostics() {
+		if diag.GetSeverity() == tfplugin6.Diagnostic_ERROR {
+			errs = append(errs, diagnosticText(diag.GetSummary(), diag.GetDetail()))
+		}
+	}
+	if err := diagnosticsError(errs); err != nil {
+		return nil, err
+	}
+
+	c := &converter{}
+	ps := &tfjson.ProviderSchema{
+		ResourceSchemas:          c.schemasV6(resp.GetResourceSchemas()),
+		DataSourceSchemas:        c.schemasV6(resp.GetDataSourceSchemas()),
+		EphemeralResourceSchemas: c.schemasV6(resp.GetEphemeralResourceSchemas()),
+	}
+	if resp.GetProvider() != nil {
+		ps.ConfigSchema = c.schemaV6(resp.GetProvider())
+	}
+	if len(resp.GetFunctions()) > 0 {
+		ps.Functions = make(map[string]*tfjson.FunctionSignature, len(resp.GetFunctions()))
+		for name, f := range resp.GetFunctions() {
+			sig := &tfjson.FunctionSignature{
+				Summary:            f.GetSummary(),
+				Description:        f.GetDescription(),
+				DeprecationMessage: f.GetDeprecationMessage(),
+				ReturnType:         c.ctyType(f.GetReturn().GetType()),
+			}
+			for _, p := range f.GetParameters() {
+				sig.Parameters = append(sig.Parameters, c.parameter(p.GetName(), p.GetDescription(), p.GetAllowNullValue(), p.GetType()))
+			}
+			if p := f.GetVariadicParameter(); p != nil {
+				sig.VariadicParameter = c.parameter(p.GetName(), p.GetDescription(), p.GetAllowNullValue(), p.GetType())
+			}
+			ps.Functions[name] = sig
+		}
+	}
+	return ps, c.err
+}
+
+func (c *converter) schemasV6(in map[string]*tfplugin6.Schema) map[string]*tfjson.Schema {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]*tfjson.Schema, len(in))
+	for name, s := range in {
+		out[name] = c.schemaV6(s)
+	}
+	return out
+}
+
+func (c *converter) schemaV6(s *tfplugin6.Schema) *tfjson.Schema {
+	return &tfjson.Schema{
+		Version: uint64(s.GetVersion()),
+		Block:   c.blockV6(s.GetBlock()),
+	}
+}
+
+func (c *converter) blockV6(b *tfplugin6.Schema_Block) *tfjson.SchemaBlock {
+	block := &tfjson.SchemaBlock{
+		Description:     b.GetDescription(),
+		DescriptionKind: descriptionKind(b.GetDescriptionKind() == tfplugin6.StringKind_MARKDOWN),
+		Deprecated:      b.GetDeprecated(),
+	}
+	if attrs := b.GetAttributes(); len(attrs) > 0 {
+		block.Attributes = make(map[string]*tfjson.SchemaAttribute, len(attrs))
+		for _, a := range attrs {
+			block.Attributes[a.GetName()] = c.attributeV6(a)
+		}
+	}
+	if nested := b.GetBlockTypes(); len(nested) > 0 {
+		block.NestedBlocks = make(map[string]*tfjson.SchemaBlockType, len(nested))
+		for _, nb := range nested {
+			block.NestedBlocks[nb.GetTypeName()] = &tfjson.SchemaBlockType{
+				NestingMode: nestingMode(nb.GetNesting().String()),
+				Block:       c.blockV6(nb.GetBlock()),
+				MinItems:    uint64(nb.GetMinItems()),
+				MaxItems:    uint64(nb.GetMaxItems()),
+			}
+		}
+	}
+	return block
+}
+
+func (c *converter) attributeV6(a *tfplugin6.Schema_Attribute) *tfjson.SchemaAttribute {
+	attr := &tfjson.SchemaAttribute{
+		Description:     a.GetDescription(),
+		DescriptionKind: descriptionKind(a.GetDescriptionKind() == tfplugin6.StringKind_MARKDOWN),
+		Deprecated:      a.GetDeprecated(),
+		Required:        a.GetRequired(),
+		Optional:        a.GetOptional(),
+		Computed:        a.GetComputed(),
+		Sensitive:       a.GetSensitive(),
+		WriteOnly:       a.GetWriteOnly(),
+	}
+	if obj := a.GetNestedType(); obj != nil {
+		nested := &tfjson.SchemaNestedAttributeType{
+			Attributes:  make(map[string]*tfjson.SchemaAttribute, len(obj.GetAttributes())),
+			NestingMode: nestingMode(obj.GetNesting().String()),
+		}
+		for _, na := range obj.GetAttributes() {
+			nested.Attributes[na.GetName()] = c.attributeV6(na)
+		}
+		attr.AttributeNestedType = nested
+	} else {
+		attr.AttributeType = c.ctyType(a.GetType())
+	}
+	return attr
+}
+
+func convertV5(resp *tfplugin5.GetProviderSchema_Response) (*tfjson.ProviderSchema, error) {
+	var errs []string
+	for _, diag := range resp.GetDiagnostics() {
+		if diag.GetSeverity() == tfplugin5.Diagnostic_ERROR {
+			errs = append(errs, diagnosticText(diag.GetSummary(), diag.GetDetail()))
+		}
+	}
+	if err := diagnosticsError(errs); err != nil {
+		return nil, err
+	}
+
+	c := &converter{}
+	ps := &tfjson.ProviderSchema{
+		ResourceSchemas:          c.schemasV5(resp.GetResourceSchemas()),
+		DataSourceSchemas:        c.schemasV5(resp.GetDataSourceSchemas()),
+		EphemeralResourceSchemas: c.schemasV5(resp.GetEphemeralResourceSchemas()),
+	}
+	if resp.GetProvider() != nil {
+		ps.ConfigSchema = c.schemaV5(resp.GetProvider())
+	}
+	if len(resp.GetFunctions()) > 0 {
+		ps.Functions = make(map[string]*tfjson.FunctionSignature, len(resp.GetFunctions()))
+		for name, f := range resp.GetFunctions() {
+			sig := &tfjson.FunctionSignature{
+				Summary:            f.GetSummary(),
+				Description:        f.GetDescription(),
+				DeprecationMessage: f.GetDeprecationMessage(),
+				ReturnType:         c.ctyType(f.GetReturn().GetType()),
+			}
+			for _, p := range f.GetParameters() {
+				sig.Parameters = append(sig.Parameters, c.parameter(p.GetName(), p.GetDescription(), p.GetAllowNullValue(), p.GetType()))
+			}
+			if p := f.GetVariadicParameter(); p != nil {
+				sig.VariadicParameter = c.parameter(p.GetName(), p.GetDescription(), p.GetAllowNullValue(), p.GetType())
+			}
+			ps.Functions[name] = sig
+		}
+	}
+	return ps, c.err
+}
+
+func (c *converter) schemasV5(in map[string]*tfplugin5.Schema) map[string]*tfjson.Schema {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]*tfjson.Schema, len(in))
+	for name, s := range in {
+		out[name] = c.schemaV5(s)
+	}
+	return out
+}
+
+func (c *converter) schemaV5(s *tfplugin5.Schema) *tfjson.Schema {
+	return &tfjson.Schema{
+		Version: uint64(s.GetVersion()),
+		Block:   c.blockV5(s.GetBlock()),
+	}
+}
+
+func (c *converter) blockV5(b *tfplugin5.Schema_Block) *tfjson.SchemaBlock {
+	block := &tfjson.SchemaBlock{
+		Description:     b.GetDescription(),
+		DescriptionKind: descriptionKind(b.GetDescriptionKind() == tfplugin5.StringKind_MARKDOWN),
+		Deprecated:      b.GetDeprecated(),
+	}
+	if attrs := b.GetAttributes(); len(attrs) > 0 {
+		block.Attributes = make(map[string]*tfjson.SchemaAttribute, len(attrs))
+		for _, a := range attrs {
+			block.Attributes[a.GetName()] = &tfjson.SchemaAttribute{
+				AttributeType:   c.ctyType(a.GetType()),
+				Description:     a.GetDescription(),
+				DescriptionKind: descriptionKind(a.GetDescriptionKind() == tfplugin5.StringKind_MARKDOWN),
+				Deprecated:      a.GetDeprecated(),
+				Required:        a.GetRequired(),
+				Optional:        a.GetOptional(),
+				Computed:        a.GetComputed(),
+				Sensitive:       a.GetSensitive(),
+				WriteOnly:       a.GetWriteOnly(),
+			}
+		}
+	}
+	if nested := b.GetBlockTypes(); len(nested) > 0 {
+		block.NestedBlocks = make(map[string]*tfjson.SchemaBlockType, len(nested))
+		for _, nb := range nested {
+			block.NestedBlocks[nb.GetTypeName()] = &tfjson.SchemaBlockType{
+				NestingMode: nestingMode(nb.GetNesting().String()),
+				Block:       c.blockV5(nb.GetBlock()),
+				MinItems:    uint64(nb.GetMinItems()),
+				MaxItems:    uint64(nb.GetMaxItems()),
+			}
+		}
+	}
+	return block
+}
+
+// converter remembers the first type it failed to decode so the walk can stay simple
+type converter struct {
+	err error
+}
+
+// ctyType decodes a type constraint sent in its JSON form
+func (c *converter) ctyType(raw []byte) cty.Type {
+	if len(raw) == 0 {
+		return cty.NilType
+	}
+	ty, err := ctyjson.UnmarshalType(raw)
+	if err != nil && c.err == nil {
+		c.err = fmt.Errorf("invalid type %s in provider schema: %w", raw, err)
+	}
+	return ty
+}
+
+func (c *converter) parameter(name, description string, nullable bool, raw []byte) *tfjson.FunctionParameter {
+	return &tfjson.FunctionParameter{
+		Name:        name,
+		Description: description,
+		IsNullable:  nullable,
+		Type:        c.ctyType(raw),
+	}
+}
+
+// nestingMode maps the protocol enum names (SINGLE, LIST, ...) onto tfjson's
+func nestingMode(name string) tfjson.SchemaNestingMode {
+	return tfjson.SchemaNestingMode(strings.ToLower(name))
+}
+
+func descriptionKind(markdown bool) tfjson.SchemaDescriptionKind {
+	if markdown {
+		return tfjson.SchemaDescriptionKindMarkdown
+	}
+	return tfjson.SchemaDescriptionKindPlain
+}
+
+func diagnosticText(summary, detail string) string {
+	if detail == "" {
+		return summary
+	}
+	return summary + ": " + detail
+}
+
+func diagnosticsError(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New("provider reported errors: " + strings.Join(errs, "; "))
+}
@@ -0,0 +1,126 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	tfjson "github.com/hashicorp/terraform-json"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentPlugins bounds how many providers are started at once; large providers
+// use a lot of memory while they build their schema
+const maxConcurrentPlugins = 4
+
+// Provider is an installed provider plugin
+type Provider struct {
+	Source  string // full source address, e.g. registry.terraform.io/hashicorp/aws
+	Version string
+	Path    string // the plugin executable
+}
+
+// FindProviders lists the provider plugins for the current platform in dirs, which use
+// the layout terraform and tofu share for .terraform/providers and plugin cache
+// directories: <host>/<namespace>/<type>/<version>/<os>_<arch>/terraform-provider-<type>*.
+//
+// When selections is non-nil only the selected version of each selected provider is
+// returned, otherwise the newest installed version of each provider. A provider found
+// in several directories is taken from the first.
+func FindProviders(dirs []string, selections map[string]string) []Provider {
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	found := make(map[string]Provider)
+
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*", "*", "*", "*", platform, "terraform-provider-*"))
+		sort.Strings(matches)
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				continue
+			}
+			parts := strings.Split(filepath.ToSlash(rel), "/")
+			p := Provider{
+				Source:  strings.Join(parts[:3], "/"),
+				Version: parts[3],
+				Path:    path,
+			}
+
+			if selections != nil {
+				if selections[p.Source] != p.Version {
+					continue
+				}
+				if _, ok := found[p.Source]; !ok {
+					found[p.Source] = p
+				}
+				continue
+			}
+			if current, ok := found[p.Source]; !ok || newerVersion(p.Version, current.Version) {
+				found[p.Source] = p
+			}
+		}
+	}
+
+	providers := make([]Provider, 0, len(found))
+	for _, p := range found {
+		providers = append(providers, p)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Source < providers[j].Source
+	})
+	return providers
+}
+
+// newerVersion reports whether version a is newer than b, falling back to a plain
+// string comparison for versions that are not semver
+func newerVersion(a, b string) bool {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA != nil || errB != nil {
+		return a > b
+	}
+	return va.GreaterThan(vb)
+}
+
+// LoadProviderSchemas starts every provider and collects their schemas into a document
+// shaped like the output of `providers schema -json`
+func LoadProviderSchemas(ctx context.Context, providers []Provider) (*tfjson.ProviderSchemas, error) {
+	results := make([]*tfjson.ProviderSchema, len(providers))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentPlugins)
+	for i, p := range providers {
+		g.Go(func() error {
+			ps, err := GetProviderSchema(ctx, p.Path)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", p.Source, p.Version, err)
+			}
+			results[i] = ps
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	schemas := &tfjson.ProviderSchemas{
+		FormatVersion: "1.0",
+		Schemas:       make(map[string]*tfjson.ProviderSchema, len(providers)),
+	}
+	for i, p := range providers {
+		schemas.Schemas[p.Source] = results[i]
+	}
+	return schemas, nil
+}
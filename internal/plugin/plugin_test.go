@@ -0,0 +1,135 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// buildTestProvider builds testdata/testprovider for the given plugin protocol and
+// installs it under dir in the .terraform/providers layout
+func buildTestProvider(t *testing.T, dir, source, version, protocol string) string {
+	t.Helper()
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	path := filepath.Join(dir, filepath.FromSlash(source), version, platform, "terraform-provider-"+filepath.Base(source)+"_v"+version)
+
+	cmd := exec.Command("go", "build", "-o", path, "-ldflags", "-X main.protocol="+protocol, "./testdata/testprovider")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return path
+}
+
+func TestGetProviderSchema_Protocol6(t *testing.T) {
+	path := buildTestProvider(t, t.TempDir(), "example.com/test/test", "1.0.0", "6")
+
+	ps, err := GetProviderSchema(context.Background(), path)
+	require.NoError(t, err)
+
+	require.Equal(t, cty.String, ps.ConfigSchema.Block.Attributes["endpoint"].AttributeType)
+
+	thing := ps.ResourceSchemas["test_thing"]
+	require.Equal(t, uint64(2), thing.Version)
+	require.Equal(t, &tfjson.SchemaAttribute{
+		AttributeType:   cty.String,
+		Description:     "The **name**.",
+		DescriptionKind: tfjson.SchemaDescriptionKindMarkdown,
+		Required:        true,
+	}, thing.Block.Attributes["name"])
+	require.True(t, thing.Block.Attributes["id"].Computed)
+
+	ports := thing.Block.Attributes["ports"]
+	require.Equal(t, cty.NilType, ports.AttributeType)
+	require.Equal(t, tfjson.SchemaNestingModeList, ports.AttributeNestedType.NestingMode)
+	require.Equal(t, cty.Number, ports.AttributeNestedType.Attributes["number"].AttributeType)
+
+	timeouts := thing.Block.NestedBlocks["timeouts"]
+	require.Equal(t, tfjson.SchemaNestingModeSingle, timeouts.NestingMode)
+	require.Contains(t, timeouts.Block.Attributes, "create")
+
+	require.Equal(t, cty.Map(cty.String), ps.DataSourceSchemas["test_info"].Block.Attributes["tags"].AttributeType)
+	require.Nil(t, ps.EphemeralResourceSchemas)
+
+	upper := ps.Functions["upper"]
+	require.Equal(t, "Upper-cases a string", upper.Summary)
+	require.Equal(t, cty.String, upper.ReturnType)
+	require.Equal(t, []*tfjson.FunctionParameter{{Name: "input", Type: cty.String}}, upper.Parameters)
+}
+
+func TestGetProviderSchema_Protocol5(t *testing.T) {
+	path := buildTestProvider(t, t.TempDir(), "example.com/test/test", "1.0.0", "5")
+
+	ps, err := GetProviderSchema(context.Background(), path)
+	require.NoError(t, err)
+
+	thing := ps.ResourceSchemas["test_thing"]
+	require.Equal(t, uint64(1), thing.Version)
+	require.Equal(t, &tfjson.SchemaAttribute{
+		AttributeType:   cty.String,
+		DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+		Optional:        true,
+		Sensitive:       true,
+		WriteOnly:       true,
+	}, thing.Block.Attributes["secret"])
+	require.Equal(t, &tfjson.SchemaBlockType{
+		NestingMode: tfjson.SchemaNestingModeList,
+		MinItems:    1,
+		MaxItems:    3,
+		Block: &tfjson.SchemaBlock{
+			DescriptionKind: tfjson.SchemaDescriptionKindPlain,
+			Attributes: map[string]*tfjson.SchemaAttribute{
+				"priority": {AttributeType: cty.Number, DescriptionKind: tfjson.SchemaDescriptionKindPlain, Optional: true},
+			},
+		},
+	}, thing.Block.NestedBlocks["rule"])
+	require.Nil(t, ps.ConfigSchema)
+}
+
+func TestGetProviderSchema_NotAPlugin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "terraform-provider-broken")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho 'not a plugin' >&2\nexit 1\n"), 0755))
+
+	_, err := GetProviderSchema(context.Background(), path)
+	require.ErrorContains(t, err, "not a plugin")
+}
+
+func TestFindProviders(t *testing.T) {
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	install := func(dir, source, version, osArch string) string {
+		path := filepath.Join(dir, filepath.FromSlash(source), version, osArch, "terraform-provider-"+filepath.Base(source)+"_v"+version)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), 0755))
+		return path
+	}
+
+	workspace := t.TempDir()
+	cache := t.TempDir()
+	nullInstalled := install(workspace, "registry.terraform.io/hashicorp/null", "3.2.3", platform)
+	install(cache, "registry.terraform.io/hashicorp/null", "3.2.3", platform)
+	install(cache, "registry.terraform.io/hashicorp/null", "3.2.4", platform)
+	random10 := install(cache, "registry.terraform.io/hashicorp/random", "3.10.0", platform)
+	install(cache, "registry.terraform.io/hashicorp/random", "3.9.0", platform)
+	install(cache, "registry.terraform.io/hashicorp/aws", "6.0.0", "plan9_mips")
+
+	// Without selections the newest version wins
+	providers := FindProviders([]string{workspace, cache}, nil)
+	require.Equal(t, []Provider{
+		{Source: "registry.terraform.io/hashicorp/null", Version: "3.2.4", Path: filepath.Join(cache, "registry.terraform.io/hashicorp/null/3.2.4", platform, "terraform-provider-null_v3.2.4")},
+		{Source: "registry.terraform.io/hashicorp/random", Version: "3.10.0", Path: random10},
+	}, providers)
+
+	// Selections pick exact versions, preferring the earlier directory
+	providers = FindProviders([]string{workspace, cache}, map[string]string{
+		"registry.terraform.io/hashicorp/null": "3.2.3",
+		"registry.terraform.io/hashicorp/aws":  "6.0.0",
+	})
+	require.Equal(t, []Provider{
+		{Source: "registry.terraform.io/hashicorp/null", Version: "3.2.3", Path: nullInstalled},
+	}, providers)
+}
@@ -0,0 +1,125 @@
+// Command testprovider is a minimal provider plugin for the loader tests. It serves a
+// fixed schema over plugin protocol 6, or protocol 5 when built with
+// -ldflags "-X main.protocol=5".
+package main
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"github.com/terraconstructs/provider-explorer/internal/plugin"
+	"github.com/terraconstructs/provider-explorer/internal/plugin/tfplugin5"
+	"github.com/terraconstructs/provider-explorer/internal/plugin/tfplugin6"
+)
+
+var protocol = "6"
+
+func main() {
+	var plugins map[int]goplugin.PluginSet
+	if protocol == "5" {
+		plugins = map[int]goplugin.PluginSet{5: {"provider": &server{register: func(s *grpc.Server) {
+			tfplugin5.RegisterProviderServer(s, provider5{})
+		}}}}
+	} else {
+		plugins = map[int]goplugin.PluginSet{6: {"provider": &server{register: func(s *grpc.Server) {
+			tfplugin6.RegisterProviderServer(s, provider6{})
+		}}}}
+	}
+
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig:  plugin.Handshake,
+		VersionedPlugins: plugins,
+		GRPCServer:       goplugin.DefaultGRPCServer,
+		Logger:           hclog.NewNullLogger(),
+	})
+}
+
+type server struct {
+	goplugin.NetRPCUnsupportedPlugin
+	register func(*grpc.Server)
+}
+
+func (s *server) GRPCServer(_ *goplugin.GRPCBroker, g *grpc.Server) error {
+	s.register(g)
+	return nil
+}
+
+func (s *server) GRPCClient(context.Context, *goplugin.GRPCBroker, *grpc.ClientConn) (interface{}, error) {
+	return nil, nil
+}
+
+type provider6 struct {
+	tfplugin6.UnimplementedProviderServer
+}
+
+func (provider6) GetProviderSchema(context.Context, *tfplugin6.GetProviderSchema_Request) (*tfplugin6.GetProviderSchema_Response, error) {
+	return &tfplugin6.GetProviderSchema_Response{
+		Provider: &tfplugin6.Schema{Block: &tfplugin6.Schema_Block{
+			Attributes: []*tfplugin6.Schema_Attribute{
+				{Name: "endpoint", Type: []byte(`"string"`), Optional: true},
+			},
+		}},
+		ResourceSchemas: map[string]*tfplugin6.Schema{
+			"test_thing": {Version: 2, Block: &tfplugin6.Schema_Block{
+				Attributes: []*tfplugin6.Schema_Attribute{
+					{Name: "name", Type: []byte(`"string"`), Required: true, Description: "The **name**.", DescriptionKind: tfplugin6.StringKind_MARKDOWN},
+					{Name: "id", Type: []byte(`"string"`), Computed: true},
+					{Name: "ports", Optional: true, NestedType: &tfplugin6.Schema_Object{
+						Nesting: tfplugin6.Schema_Object_LIST,
+						Attributes: []*tfplugin6.Schema_Attribute{
+							{Name: "number", Type: []byte(`"number"`), Required: true},
+						},
+					}},
+				},
+				BlockTypes: []*tfplugin6.Schema_NestedBlock{
+					{TypeName: "timeouts", Nesting: tfplugin6.Schema_NestedBlock_SINGLE, Block: &tfplugin6.Schema_Block{
+						Attributes: []*tfplugin6.Schema_Attribute{
+							{Name: "create", Type: []byte(`"string"`), Optional: true},
+						},
+					}},
+				},
+			}},
+		},
+		DataSourceSchemas: map[string]*tfplugin6.Schema{
+			"test_info": {Block: &tfplugin6.Schema_Block{
+				Attributes: []*tfplugin6.Schema_Attribute{
+					{Name: "tags", Type: []byte(`["map","string"]`), Computed: true},
+				},
+			}},
+		},
+		Functions: map[string]*tfplugin6.Function{
+			"upper": {
+				Summary:    "Upper-cases a string",
+				Parameters: []*tfplugin6.Function_Parameter{{Name: "input", Type: []byte(`"string"`)}},
+				Return:     &tfplugin6.Function_Return{Type: []byte(`"string"`)},
+			},
+		},
+	}, nil
+}
+
+type provider5 struct {
+	tfplugin5.UnimplementedProviderServer
+}
+
+func (provider5) GetSchema(context.Context, *tfplugin5.GetProviderSchema_Request) (*tfplugin5.GetProviderSchema_Response, error) {
+	return &tfplugin5.GetProviderSchema_Response{
+		ResourceSchemas: map[string]*tfplugin5.Schema{
+			"test_thing": {Version: 1, Block: &tfplugin5.Schema_Block{
+				Attributes: []*tfplugin5.Schema_Attribute{
+					{Name: "name", Type: []byte(`"string"`), Required: true},
+					{Name: "secret", Type: []byte(`"string"`), Optional: true, Sensitive: true, WriteOnly: true},
+				},
+				BlockTypes: []*tfplugin5.Schema_NestedBlock{
+					{TypeName: "rule", Nesting: tfplugin5.Schema_NestedBlock_LIST, MinItems: 1, MaxItems: 3, Block: &tfplugin5.Schema_Block{
+						Attributes: []*tfplugin5.Schema_Attribute{
+							{Name: "priority", Type: []byte(`"number"`), Optional: true},
+						},
+					}},
+				},
+			}},
+		},
+	}, nil
+}
@@ -0,0 +1,8 @@
+// Package tfplugin6 holds the Terraform plugin protocol version 6 definition and the
+// Go stubs generated from it, vendored unmodified from terraform-plugin-go v0.29.0.
+// See ATTRIBUTION.md.
+package tfplugin6
+
+// Regenerate with protoc v5.29.3, protoc-gen-go v1.36.9 and protoc-gen-go-grpc v1.5.1
+// on PATH; the output must match the vendored stubs.
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative tfplugin6.proto
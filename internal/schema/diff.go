@@ -0,0 +1,467 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Severity classifies the impact of a schema change on existing configurations
+type Severity string
+
+const (
+	SeverityBreaking    Severity = "breaking"
+	SeverityNonBreaking Severity = "non-breaking"
+	SeverityDeprecation Severity = "deprecation"
+)
+
+// ChangeKind describes what changed between two schema versions
+type ChangeKind string
+
+const (
+	ChangeAdded            ChangeKind = "added"
+	ChangeRemoved          ChangeKind = "removed"
+	ChangeBecameRequired   ChangeKind = "became_required"
+	ChangeBecameOptional   ChangeKind = "became_optional"
+	ChangeTypeChanged      ChangeKind = "type_changed"
+	ChangeComputedOnly     ChangeKind = "became_computed_only"
+	ChangeConfigurable     ChangeKind = "became_configurable"
+	ChangeBecameSensitive  ChangeKind = "became_sensitive"
+	ChangeNestingChanged   ChangeKind = "nesting_changed"
+	ChangeItemsChanged     ChangeKind = "items_changed"
+	ChangeSignatureChanged ChangeKind = "signature_changed"
+	ChangeDeprecated       ChangeKind = "deprecated"
+	ChangeUndeprecated     ChangeKind = "undeprecated"
+)
+
+// KindProviderConfig marks changes to the arguments of the provider block itself
+const KindProviderConfig EntityKind = "provider"
+
+// SchemaChange is a single difference between two provider schemas.
+// Path is empty for changes to a whole provider or entity.
+type SchemaChange struct {
+	Severity   Severity   `json:"severity"`
+	Kind       ChangeKind `json:"kind"`
+	Provider   string     `json:"provider"`
+	EntityKind EntityKind `json:"entity_kind,omitempty"`
+	Entity     string     `json:"entity,omitempty"`
+	Path       string     `json:"path,omitempty"`
+	Detail     string     `json:"detail"`
+}
+
+// Subject renders what the change applies to, e.g. "resource aws_instance.root_block_device.volume_size"
+func (c SchemaChange) Subject() string {
+	if c.Entity == "" {
+		return "provider " + c.Provider
+	}
+	subject := fmt.Sprintf("%s %s", c.EntityKind, c.Entity)
+	if c.Path != "" {
+		subject += "." + c.Path
+	}
+	return subject
+}
+
+// SchemaDiff is the categorised result of comparing two provider schema documents
+type SchemaDiff struct {
+	Breaking     []SchemaChange `json:"breaking"`
+	NonBreaking  []SchemaChange `json:"non_breaking"`
+	Deprecations []SchemaChange `json:"deprecations"`
+}
+
+// HasBreaking reports whether any breaking changes were found
+func (d *SchemaDiff) HasBreaking() bool {
+	return len(d.Breaking) > 0
+}
+
+// IsEmpty reports whether the two schemas are equivalent
+func (d *SchemaDiff) IsEmpty() bool {
+	return len(d.Breaking) == 0 && len(d.NonBreaking) == 0 && len(d.Deprecations) == 0
+}
+
+func (d *SchemaDiff) add(c SchemaChange) {
+	switch c.Severity {
+	case SeverityBreaking:
+		d.Breaking = append(d.Breaking, c)
+	case SeverityDeprecation:
+		d.Deprecations = append(d.Deprecations, c)
+	default:
+		d.NonBreaking = append(d.NonBreaking, c)
+	}
+}
+
+// DiffProviderSchemas compares two provider schema documents and classifies every
+// difference as breaking, non-breaking or a deprecation.
+func DiffProviderSchemas(oldSchemas, newSchemas *ProviderSchemas) *SchemaDiff {
+	// Empty slices rather than nil so JSON output always has all three lists
+	d := &SchemaDiff{Breaking: []SchemaChange{}, NonBreaking: []SchemaChange{}, Deprecations: []SchemaChange{}}
+	oldProviders := providerMap(oldSchemas)
+	newProviders := providerMap(newSchemas)
+
+	for _, name := range unionKeys(oldProviders, newProviders) {
+		oldProvider, inOld := oldProviders[name]
+		newProvider, inNew := newProviders[name]
+		switch {
+		case !inNew:
+			d.add(SchemaChange{Severity: SeverityBreaking, Kind: ChangeRemoved, Provider: name, Detail: "provider removed"})
+		case !inOld:
+			d.add(SchemaChange{Severity: SeverityNonBreaking, Kind: ChangeAdded, Provider: name, Detail: "provider added"})
+		default:
+			diffProvider(d, name, oldProvider, newProvider)
+		}
+	}
+
+	sortChanges(d.Breaking)
+	sortChanges(d.NonBreaking)
+	sortChanges(d.Deprecations)
+	return d
+}
+
+func providerMap(ps *ProviderSchemas) map[string]*ProviderSchema {
+	if ps == nil || ps.Schemas == nil {
+		return map[string]*ProviderSchema{}
+	}
+	return ps.Schemas
+}
+
+func diffProvider(d *SchemaDiff, provider string, oldProvider, newProvider *ProviderSchema) {
+	if oldProvider == nil {
+		oldProvider = &ProviderSchema{}
+	}
+	if newProvider == nil {
+		newProvider = &ProviderSchema{}
+	}
+
+	// The provider block is named by its type, as in provider "aws" {}
+	config := SchemaChange{Provider: provider, EntityKind: KindProviderConfig, Entity: provider[strings.LastIndex(provider, "/")+1:]}
+	diffBlock(d, config, nil, blockOf(oldProvider.ConfigSchema), blockOf(newProvider.ConfigSchema))
+
+	diffEntities(d, provider, KindResource, oldProvider.ResourceSchemas, newProvider.ResourceSchemas)
+	diffEntities(d, provider, KindDataSource, oldProvider.DataSourceSchemas, newProvider.DataSourceSchemas)
+	diffEntities(d, provider, KindEphemeralResource, oldProvider.EphemeralResourceSchemas, newProvider.EphemeralResourceSchemas)
+
+	for _, name := range unionKeys(oldProvider.Functions, newProvider.Functions) {
+		oldFn, inOld := oldProvider.Functions[name]
+		newFn, inNew := newProvider.Functions[name]
+		base := SchemaChange{Provider: provider, EntityKind: KindFunction, Entity: name}
+		switch {
+		case !inNew:
+			d.add(withChange(base, SeverityBreaking, ChangeRemoved, "function removed"))
+		case !inOld:
+			d.add(withChange(base, SeverityNonBreaking, ChangeAdded, "function added"))
+		default:
+			diffFunction(d, base, oldFn, newFn)
+		}
+	}
+}
+
+func diffEntities(d *SchemaDiff, provider string, kind EntityKind, oldEntities, newEntities map[string]*Schema) {
+	for _, name := range unionKeys(oldEntities, newEntities) {
+		oldEntity, inOld := oldEntities[name]
+		newEntity, inNew := newEntities[name]
+		base := SchemaChange{Provider: provider, EntityKind: kind, Entity: name}
+		switch {
+		case !inNew:
+			d.add(withChange(base, SeverityBreaking, ChangeRemoved, fmt.Sprintf("%s removed", kind)))
+		case !inOld:
+			d.add(withChange(base, SeverityNonBreaking, ChangeAdded, fmt.Sprintf("%s added", kind)))
+		default:
+			diffBlock(d, base, nil, blockOf(oldEntity), blockOf(newEntity))
+		}
+	}
+}
+
+func blockOf(s *Schema) *tfjson.SchemaBlock {
+	if s == nil || s.Block == nil {
+		return &tfjson.SchemaBlock{}
+	}
+	return s.Block
+}
+
+func diffBlock(d *SchemaDiff, base SchemaChange, path []string, oldBlock, newBlock *tfjson.SchemaBlock) {
+	at := func(name string) SchemaChange {
+		c := base
+		c.Path = strings.Join(append(append([]string(nil), path...), name), ".")
+		return c
+	}
+	self := base
+	self.Path = strings.Join(path, ".")
+
+	if !oldBlock.Deprecated && newBlock.Deprecated {
+		d.add(withChange(self, SeverityDeprecation, ChangeDeprecated, "deprecated"))
+	} else if oldBlock.Deprecated && !newBlock.Deprecated {
+		d.add(withChange(self, SeverityNonBreaking, ChangeUndeprecated, "no longer deprecated"))
+	}
+
+	diffAttributes(d, base, path, oldBlock.Attributes, newBlock.Attributes)
+
+	for _, name := range unionKeys(oldBlock.NestedBlocks, newBlock.NestedBlocks) {
+		oldNested, inOld := oldBlock.NestedBlocks[name]
+		newNested, inNew := newBlock.NestedBlocks[name]
+		c := at(name)
+		switch {
+		case !inNew:
+			d.add(withChange(c, SeverityBreaking, ChangeRemoved, "block removed"))
+		case !inOld:
+			if newNested != nil && newNested.MinItems > 0 {
+				d.add(withChange(c, SeverityBreaking, ChangeAdded, fmt.Sprintf("required block added (min_items = %d)", newNested.MinItems)))
+			} else {
+				d.add(withChange(c, SeverityNonBreaking, ChangeAdded, "block added"))
+			}
+		default:
+			diffBlockType(d, c, base, append(append([]string(nil), path...), name), oldNested, newNested)
+		}
+	}
+}
+
+func diffBlockType(d *SchemaDiff, c, base SchemaChange, path []string, oldNested, newNested *tfjson.SchemaBlockType) {
+	if oldNested == nil || newNested == nil {
+		return
+	}
+	if oldNested.NestingMode != newNested.NestingMode {
+		d.add(withChange(c, SeverityBreaking, ChangeNestingChanged, fmt.Sprintf("nesting mode changed from %s to %s", oldNested.NestingMode, newNested.NestingMode)))
+	}
+	d.addItems(c, oldNested.MinItems, newNested.MinItems, oldNested.MaxItems, newNested.MaxItems)
+
+	oldBlock, newBlock := oldNested.Block, newNested.Block
+	if oldBlock == nil {
+		oldBlock = &tfjson.SchemaBlock{}
+	}
+	if newBlock == nil {
+		newBlock = &tfjson.SchemaBlock{}
+	}
+	diffBlock(d, base, path, oldBlock, newBlock)
+}
+
+// addItems records min/max item changes; tightening either bound is breaking
+func (d *SchemaDiff) addItems(c SchemaChange, oldMin, newMin, oldMax, newMax uint64) {
+	if newMin > oldMin {
+		d.add(withChange(c, SeverityBreaking, ChangeItemsChanged, fmt.Sprintf("min_items increased from %d to %d", oldMin, newMin)))
+	} else if newMin < oldMin {
+		d.add(withChange(c, SeverityNonBreaking, ChangeItemsChanged, fmt.Sprintf("min_items decreased from %d to %d", oldMin, newMin)))
+	}
+	// A max of 0 means unbounded
+	switch {
+	case oldMax == newMax:
+	case newMax != 0 && (oldMax == 0 || newMax < oldMax):
+		d.add(withChange(c, SeverityBreaking, ChangeItemsChanged, fmt.Sprintf("max_items limited to %d", newMax)))
+	default:
+		d.add(withChange(c, SeverityNonBreaking, ChangeItemsChanged, "max_items relaxed"))
+	}
+}
+
+func diffAttributes(d *SchemaDiff, base SchemaChange, path []string, oldAttrs, newAttrs map[string]*tfjson.SchemaAttribute) {
+	for _, name := range unionKeys(oldAttrs, newAttrs) {
+		oldAttr, inOld := oldAttrs[name]
+		newAttr, inNew := newAttrs[name]
+		attrPath := append(append([]string(nil), path...), name)
+		c := base
+		c.Path = strings.Join(attrPath, ".")
+
+		switch {
+		case !inNew:
+			d.add(withChange(c, SeverityBreaking, ChangeRemoved, "attribute removed"))
+		case !inOld:
+			if newAttr != nil && newAttr.Required {
+				d.add(withChange(c, SeverityBreaking, ChangeAdded, "required attribute added"))
+			} else {
+				d.add(withChange(c, SeverityNonBreaking, ChangeAdded, "attribute added"))
+			}
+		case oldAttr != nil && newAttr != nil:
+			diffAttribute(d, c, base, attrPath, oldAttr, newAttr)
+		}
+	}
+}
+
+func diffAttribute(d *SchemaDiff, c, base SchemaChange, path []string, oldAttr, newAttr *tfjson.SchemaAttribute) {
+	if !oldAttr.Required && newAttr.Required {
+		d.add(withChange(c, SeverityBreaking, ChangeBecameRequired, "became required"))
+	} else if oldAttr.Required && !newAttr.Required && newAttr.Optional {
+		d.add(withChange(c, SeverityNonBreaking, ChangeBecameOptional, "became optional"))
+	}
+
+	oldConfigurable := oldAttr.Required || oldAttr.Optional
+	newConfigurable := newAttr.Required || newAttr.Optional
+	if oldConfigurable && !newConfigurable {
+		d.add(withChange(c, SeverityBreaking, ChangeComputedOnly, "became computed-only and can no longer be set"))
+	} else if !oldConfigurable && newConfigurable {
+		d.add(withChange(c, SeverityNonBreaking, ChangeConfigurable, "can now be set in configuration"))
+	}
+
+	if !oldAttr.Sensitive && newAttr.Sensitive {
+		d.add(withChange(c, SeverityBreaking, ChangeBecameSensitive, "became sensitive; outputs referencing it must be marked sensitive"))
+	}
+
+	if !oldAttr.Deprecated && newAttr.Deprecated {
+		d.add(withChange(c, SeverityDeprecation, ChangeDeprecated, "deprecated"))
+	} else if oldAttr.Deprecated && !newAttr.Deprecated {
+		d.add(withChange(c, SeverityNonBreaking, ChangeUndeprecated, "no longer deprecated"))
+	}
+
+	oldNested, newNested := oldAttr.AttributeNestedType, newAttr.AttributeNestedType
+	switch {
+	case oldNested == nil && newNested == nil:
+		if !typesEqual(oldAttr.AttributeType, newAttr.AttributeType) {
+			d.add(withChange(c, SeverityBreaking, ChangeTypeChanged, fmt.Sprintf("type changed from %s to %s", friendlyType(oldAttr.AttributeType), friendlyType(newAttr.AttributeType))))
+		}
+	case oldNested == nil || newNested == nil:
+		d.add(withChange(c, SeverityBreaking, ChangeTypeChanged, "changed between a plain type and nested attributes"))
+	default:
+		if oldNested.NestingMode != newNested.NestingMode {
+			d.add(withChange(c, SeverityBreaking, ChangeNestingChanged, fmt.Sprintf("nesting mode changed from %s to %s", oldNested.NestingMode, newNested.NestingMode)))
+		}
+		d.addItems(c, oldNested.MinItems, newNested.MinItems, oldNested.MaxItems, newNested.MaxItems)
+		diffAttributes(d, base, path, oldNested.Attributes, newNested.Attributes)
+	}
+}
+
+func diffFunction(d *SchemaDiff, base SchemaChange, oldFn, newFn *FunctionSignature) {
+	if oldFn == nil || newFn == nil {
+		return
+	}
+	if oldFn.DeprecationMessage == "" && newFn.DeprecationMessage != "" {
+		d.add(withChange(base, SeverityDeprecation, ChangeDeprecated, "deprecated: "+newFn.DeprecationMessage))
+	}
+	if functionSignature(oldFn) != functionSignature(newFn) {
+		d.add(withChange(base, SeverityBreaking, ChangeSignatureChanged, fmt.Sprintf("signature changed from %s to %s", functionSignature(oldFn), functionSignature(newFn))))
+	}
+}
+
+func functionSignature(fn *FunctionSignature) string {
+	var params []string
+	for _, p := range fn.Parameters {
+		params = append(params, friendlyType(p.Type))
+	}
+	if fn.VariadicParameter != nil {
+		params = append(params, "..."+friendlyType(fn.VariadicParameter.Type))
+	}
+	return fmt.Sprintf("(%s) %s", strings.Join(params, ", "), friendlyType(fn.ReturnType))
+}
+
+func typesEqual(a, b cty.Type) bool {
+	if a == cty.NilType || b == cty.NilType {
+		return a == b
+	}
+	return a.Equals(b)
+}
+
+func friendlyType(t cty.Type) string {
+	if t == cty.NilType {
+		return "unknown"
+	}
+	return t.FriendlyName()
+}
+
+func withChange(c SchemaChange, severity Severity, kind ChangeKind, detail string) SchemaChange {
+	c.Severity = severity
+	c.Kind = kind
+	c.Detail = detail
+	return c
+}
+
+// unionKeys returns the sorted union of the keys of two maps
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		seen[k] = struct{}{}
+	}
+	for k := range b {
+		seen[k] = struct{}{}
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortChanges(changes []SchemaChange) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.EntityKind != b.EntityKind {
+			return a.EntityKind < b.EntityKind
+		}
+		if a.Entity != b.Entity {
+			return a.Entity < b.Entity
+		}
+		return a.Path < b.Path
+	})
+}
+
+type diffSection struct {
+	title   string
+	changes []SchemaChange
+}
+
+func (d *SchemaDiff) sections() []diffSection {
+	return []diffSection{
+		{"Breaking changes", d.Breaking},
+		{"Non-breaking changes", d.NonBreaking},
+		{"Deprecations", d.Deprecations},
+	}
+}
+
+// WriteDiffText renders the diff as plain text grouped by severity
+func WriteDiffText(w io.Writer, d *SchemaDiff) error {
+	if d.IsEmpty() {
+		_, err := fmt.Fprintln(w, "No schema changes")
+		return err
+	}
+	first := true
+	for _, section := range d.sections() {
+		if len(section.changes) == 0 {
+			continue
+		}
+		if !first {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := fmt.Fprintf(w, "%s (%d)\n", section.title, len(section.changes)); err != nil {
+			return err
+		}
+		for _, c := range section.changes {
+			if _, err := fmt.Fprintf(w, "  %s [%s]: %s\n", c.Subject(), c.Provider, c.Detail); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteDiffMarkdown renders the diff as Markdown suitable for changelogs and PR comments
+func WriteDiffMarkdown(w io.Writer, d *SchemaDiff) error {
+	if d.IsEmpty() {
+		_, err := fmt.Fprintln(w, "No schema changes.")
+		return err
+	}
+	first := true
+	for _, section := range d.sections() {
+		if len(section.changes) == 0 {
+			continue
+		}
+		if !first {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := fmt.Fprintf(w, "## %s (%d)\n\n", section.title, len(section.changes)); err != nil {
+			return err
+		}
+		for _, c := range section.changes {
+			if _, err := fmt.Fprintf(w, "- `%s` (%s): %s\n", c.Subject(), c.Provider, c.Detail); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package schema
+
+import (
+	"bytes"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func diffFixture(instance *Schema, functions map[string]*FunctionSignature) *ProviderSchemas {
+	return &ProviderSchemas{
+		Schemas: map[string]*ProviderSchema{
+			"registry.terraform.io/hashicorp/aws": {
+				ResourceSchemas: map[string]*Schema{"aws_instance": instance},
+				Functions:       functions,
+			},
+		},
+	}
+}
+
+func TestDiffProviderSchemas(t *testing.T) {
+	oldInstance := &Schema{Block: &tfjson.SchemaBlock{
+		Attributes: map[string]*tfjson.SchemaAttribute{
+			"ami":           {AttributeType: cty.String, Required: true},
+			"instance_type": {AttributeType: cty.String, Optional: true},
+			"cpu_count":     {AttributeType: cty.Number, Optional: true},
+			"user_data":     {AttributeType: cty.String, Optional: true},
+			"arn":           {AttributeType: cty.String, Computed: true},
+		},
+		NestedBlocks: map[string]*tfjson.SchemaBlockType{
+			"root_block_device": {
+				NestingMode: tfjson.SchemaNestingModeList,
+				MaxItems:    1,
+				Block: &tfjson.SchemaBlock{Attributes: map[string]*tfjson.SchemaAttribute{
+					"volume_size": {AttributeType: cty.Number, Optional: true},
+				}},
+			},
+		},
+	}}
+	newInstance := &Schema{Block: &tfjson.SchemaBlock{
+		Attributes: map[string]*tfjson.SchemaAttribute{
+			"ami":           {AttributeType: cty.String, Required: true},
+			"instance_type": {AttributeType: cty.String, Required: true},
+			"user_data":     {AttributeType: cty.String, Optional: true, Deprecated: true},
+			"arn":           {AttributeType: cty.String, Computed: true},
+			"tags":          {AttributeType: cty.Map(cty.String), Optional: true},
+		},
+		NestedBlocks: map[string]*tfjson.SchemaBlockType{
+			"root_block_device": {
+				NestingMode: tfjson.SchemaNestingModeList,
+				MaxItems:    1,
+				Block: &tfjson.SchemaBlock{Attributes: map[string]*tfjson.SchemaAttribute{
+					"volume_size": {AttributeType: cty.String, Optional: true},
+				}},
+			},
+		},
+	}}
+	oldFunctions := map[string]*FunctionSignature{
+		"arn_parse": {ReturnType: cty.String, Parameters: []*tfjson.FunctionParameter{{Name: "arn", Type: cty.String}}},
+	}
+	newFunctions := map[string]*FunctionSignature{
+		"arn_parse": {ReturnType: cty.DynamicPseudoType, Parameters: []*tfjson.FunctionParameter{{Name: "arn", Type: cty.String}}},
+		"arn_build": {ReturnType: cty.String},
+	}
+
+	d := DiffProviderSchemas(diffFixture(oldInstance, oldFunctions), diffFixture(newInstance, newFunctions))
+
+	subjects := func(changes []SchemaChange) []string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, string(c.Kind)+" "+c.Subject())
+		}
+		return out
+	}
+
+	require.Equal(t, []string{
+		"signature_changed function arn_parse",
+		"removed resource aws_instance.cpu_count",
+		"became_required resource aws_instance.instance_type",
+		"type_changed resource aws_instance.root_block_device.volume_size",
+	}, subjects(d.Breaking))
+	require.Equal(t, []string{
+		"added function arn_build",
+		"added resource aws_instance.tags",
+	}, subjects(d.NonBreaking))
+	require.Equal(t, []string{
+		"deprecated resource aws_instance.user_data",
+	}, subjects(d.Deprecations))
+	require.True(t, d.HasBreaking())
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiffText(&buf, d))
+	require.Contains(t, buf.String(), "Breaking changes (4)\n")
+	require.Contains(t, buf.String(), "resource aws_instance.root_block_device.volume_size [registry.terraform.io/hashicorp/aws]: type changed from number to string\n")
+}
+
+func TestDiffProviderSchemas_Identical(t *testing.T) {
+	ps := loadFixture(t)
+	d := DiffProviderSchemas(ps, ps)
+	require.True(t, d.IsEmpty())
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiffMarkdown(&buf, d))
+	require.Equal(t, "No schema changes.\n", buf.String())
+}
+
+func TestDiffProviderSchemas_ProviderConfig(t *testing.T) {
+	config := func(region *tfjson.SchemaAttribute) *ProviderSchemas {
+		return &ProviderSchemas{Schemas: map[string]*ProviderSchema{
+			"registry.terraform.io/hashicorp/aws": {ConfigSchema: &Schema{Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{"region": region},
+			}}},
+		}}
+	}
+
+	d := DiffProviderSchemas(
+		config(&tfjson.SchemaAttribute{AttributeType: cty.String, Optional: true}),
+		config(&tfjson.SchemaAttribute{AttributeType: cty.String, Required: true}),
+	)
+	require.True(t, d.HasBreaking())
+	require.Len(t, d.Breaking, 1)
+	require.Equal(t, ChangeBecameRequired, d.Breaking[0].Kind)
+	require.Equal(t, "provider aws.region", d.Breaking[0].Subject())
+}
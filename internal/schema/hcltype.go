@@ -0,0 +1,160 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// AttributeType returns the type of an attribute. Attributes of plugin framework
+// providers may nest attributes instead of declaring a type; their type is an object of
+// the nested attributes, with the ones that need not be set optional, wrapped according
+// to the nesting mode.
+func AttributeType(attr *tfjson.SchemaAttribute) cty.Type {
+	nested := attr.AttributeNestedType
+	if nested == nil {
+		return attr.AttributeType
+	}
+	attrs := make(map[string]cty.Type, len(nested.Attributes))
+	var optional []string
+	for name, child := range nested.Attributes {
+		attrs[name] = AttributeType(child)
+		if !child.Required {
+			optional = append(optional, name)
+		}
+	}
+	return WrapNesting(cty.ObjectWithOptionalAttrs(attrs, optional), nested.NestingMode)
+}
+
+// WrapNesting returns the type of nested attributes, or of a nested block, holding
+// objects of type obj in the given nesting mode
+func WrapNesting(obj cty.Type, mode tfjson.SchemaNestingMode) cty.Type {
+	switch mode {
+	case tfjson.SchemaNestingModeList:
+		return cty.List(obj)
+	case tfjson.SchemaNestingModeSet:
+		return cty.Set(obj)
+	case tfjson.SchemaNestingModeMap:
+		return cty.Map(obj)
+	}
+	return obj
+}
+
+// HCLType renders a type as the type constraint expression of a variable block, e.g.
+// list(object({ name = string, size = optional(number) })). Object attributes are
+// sorted by name. Dynamic types, and types that cannot be written in HCL, become any.
+func HCLType(t cty.Type) string {
+	switch {
+	case t == cty.NilType, t == cty.DynamicPseudoType:
+		return "any"
+	case t == cty.String:
+		return "string"
+	case t == cty.Number:
+		return "number"
+	case t == cty.Bool:
+		return "bool"
+	case t.IsListType():
+		return "list(" + HCLType(t.ElementType()) + ")"
+	case t.IsSetType():
+		return "set(" + HCLType(t.ElementType()) + ")"
+	case t.IsMapType():
+		return "map(" + HCLType(t.ElementType()) + ")"
+	case t.IsTupleType():
+		elems := make([]string, len(t.TupleElementTypes()))
+		for i, et := range t.TupleElementTypes() {
+			elems[i] = HCLType(et)
+		}
+		return "tuple([" + strings.Join(elems, ", ") + "])"
+	case t.IsObjectType():
+		attrs := t.AttributeTypes()
+		if len(attrs) == 0 {
+			return "object({})"
+		}
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fields := make([]string, len(names))
+		for i, name := range names {
+			attrType := HCLType(attrs[name])
+			if t.AttributeOptional(name) {
+				attrType = "optional(" + attrType + ")"
+			}
+			fields[i] = name + " = " + attrType
+		}
+		return "object({ " + strings.Join(fields, ", ") + " })"
+	}
+	return "any"
+}
+
+// TypeLabel renders a short label of a type for display next to an attribute name.
+// Collections show their element type, e.g. list(string) or map(object), while objects
+// and tuples are labelled by kind only; HCLType spells out their structure.
+func TypeLabel(t cty.Type) string {
+	switch {
+	case t.IsListType():
+		return "list(" + TypeLabel(t.ElementType()) + ")"
+	case t.IsSetType():
+		return "set(" + TypeLabel(t.ElementType()) + ")"
+	case t.IsMapType():
+		return "map(" + TypeLabel(t.ElementType()) + ")"
+	case t.IsTupleType():
+		return "tuple"
+	case t.IsObjectType():
+		return "object"
+	}
+	return HCLType(t)
+}
+
+// IsStructuralType reports whether a type is, or holds, an object or tuple, whose
+// structure is worth spelling out next to generated code
+func IsStructuralType(t cty.Type) bool {
+	switch {
+	case t == cty.NilType:
+		return false
+	case t.IsObjectType(), t.IsTupleType():
+		return true
+	case t.IsCollectionType():
+		return IsStructuralType(t.ElementType())
+	}
+	return false
+}
+
+// BlockNesting returns the nesting mode a nested block is written in. Lists and sets
+// limited to one item are written as a single block, so they nest like single blocks.
+func BlockNesting(nb *tfjson.SchemaBlockType) tfjson.SchemaNestingMode {
+	switch nb.NestingMode {
+	case tfjson.SchemaNestingModeList, tfjson.SchemaNestingModeSet:
+		if nb.MaxItems == 1 {
+			return tfjson.SchemaNestingModeSingle
+		}
+	case "":
+		return tfjson.SchemaNestingModeSingle
+	}
+	return nb.NestingMode
+}
+
+// BlockType returns the type of a nested block: an object of its attributes and nested
+// blocks, with the ones that need not be set optional, wrapped according to BlockNesting
+func BlockType(nb *tfjson.SchemaBlockType) cty.Type {
+	attrs := make(map[string]cty.Type)
+	var optional []string
+	if nb.Block != nil {
+		for name, attr := range nb.Block.Attributes {
+			attrs[name] = AttributeType(attr)
+			if !attr.Required {
+				optional = append(optional, name)
+			}
+		}
+		for name, child := range nb.Block.NestedBlocks {
+			attrs[name] = BlockType(child)
+			if child.MinItems == 0 {
+				optional = append(optional, name)
+			}
+		}
+	}
+	return WrapNesting(cty.ObjectWithOptionalAttrs(attrs, optional), BlockNesting(nb))
+}
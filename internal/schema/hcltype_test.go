@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestHCLType(t *testing.T) {
+	rule := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"name":    cty.String,
+		"port":    cty.Number,
+		"enabled": cty.Bool,
+	}, []string{"port", "enabled"})
+
+	tests := []struct {
+		name  string
+		ty    cty.Type
+		hcl   string
+		label string
+	}{
+		{"nil", cty.NilType, "any", "any"},
+		{"dynamic", cty.DynamicPseudoType, "any", "any"},
+		{"string", cty.String, "string", "string"},
+		{"number", cty.Number, "number", "number"},
+		{"bool", cty.Bool, "bool", "bool"},
+		{"list", cty.List(cty.String), "list(string)", "list(string)"},
+		{"set", cty.Set(cty.Number), "set(number)", "set(number)"},
+		{"map", cty.Map(cty.Bool), "map(bool)", "map(bool)"},
+		{"list of dynamic", cty.List(cty.DynamicPseudoType), "list(any)", "list(any)"},
+		{"nested collections", cty.Map(cty.List(cty.String)), "map(list(string))", "map(list(string))"},
+		{"empty object", cty.EmptyObject, "object({})", "object"},
+		{
+			"object",
+			cty.Object(map[string]cty.Type{"b": cty.Number, "a": cty.String}),
+			"object({ a = string, b = number })",
+			"object",
+		},
+		{
+			"optional attributes",
+			rule,
+			"object({ enabled = optional(bool), name = string, port = optional(number) })",
+			"object",
+		},
+		{
+			"list of objects",
+			cty.List(cty.Object(map[string]cty.Type{"id": cty.String, "tags": cty.Map(cty.String)})),
+			"list(object({ id = string, tags = map(string) }))",
+			"list(object)",
+		},
+		{
+			"nested object",
+			cty.Object(map[string]cty.Type{"inner": cty.Object(map[string]cty.Type{"x": cty.Number})}),
+			"object({ inner = object({ x = number }) })",
+			"object",
+		},
+		{"empty tuple", cty.EmptyTuple, "tuple([])", "tuple"},
+		{"tuple", cty.Tuple([]cty.Type{cty.String, cty.List(cty.Bool)}), "tuple([string, list(bool)])", "tuple"},
+		{"set of tuples", cty.Set(cty.Tuple([]cty.Type{cty.Number})), "set(tuple([number]))", "set(tuple)"},
+		{"capsule", cty.Capsule("thing", nil), "any", "any"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.hcl, HCLType(tt.ty))
+			require.Equal(t, tt.label, TypeLabel(tt.ty))
+		})
+	}
+}
+
+func TestIsStructuralType(t *testing.T) {
+	require.False(t, IsStructuralType(cty.NilType))
+	require.False(t, IsStructuralType(cty.String))
+	require.False(t, IsStructuralType(cty.Map(cty.String)))
+	require.True(t, IsStructuralType(cty.EmptyObject))
+	require.True(t, IsStructuralType(cty.EmptyTuple))
+	require.True(t, IsStructuralType(cty.List(cty.Object(map[string]cty.Type{"id": cty.String}))))
+}
+
+func TestAttributeType_NestedAttributes(t *testing.T) {
+	rules := &tfjson.SchemaAttribute{
+		Optional: true,
+		AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+			NestingMode: tfjson.SchemaNestingModeList,
+			Attributes: map[string]*tfjson.SchemaAttribute{
+				"port": {AttributeType: cty.Number, Required: true},
+				"cidr": {AttributeType: cty.String, Optional: true},
+				"target": {
+					Optional: true,
+					AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+						NestingMode: tfjson.SchemaNestingModeSingle,
+						Attributes: map[string]*tfjson.SchemaAttribute{
+							"id": {AttributeType: cty.String, Required: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ty := AttributeType(rules)
+	require.Equal(t, "list(object({ cidr = optional(string), port = number, target = optional(object({ id = string })) }))", HCLType(ty))
+	require.Equal(t, "list(object)", TypeLabel(ty))
+
+	for mode, want := range map[tfjson.SchemaNestingMode]string{
+		tfjson.SchemaNestingModeSingle: "object({})",
+		tfjson.SchemaNestingModeGroup:  "object({})",
+		tfjson.SchemaNestingModeSet:    "set(object({}))",
+		tfjson.SchemaNestingModeMap:    "map(object({}))",
+	} {
+		require.Equal(t, want, HCLType(WrapNesting(cty.EmptyObject, mode)), mode)
+	}
+
+	require.Equal(t, cty.String, AttributeType(&tfjson.SchemaAttribute{AttributeType: cty.String}))
+}
+
+func TestBlockType(t *testing.T) {
+	block := &tfjson.SchemaBlock{
+		Attributes: map[string]*tfjson.SchemaAttribute{
+			"name": {AttributeType: cty.String, Required: true},
+			"size": {AttributeType: cty.Number, Optional: true},
+		},
+		NestedBlocks: map[string]*tfjson.SchemaBlockType{
+			"rule": {NestingMode: tfjson.SchemaNestingModeSet, MinItems: 1, Block: &tfjson.SchemaBlock{
+				Attributes: map[string]*tfjson.SchemaAttribute{"port": {AttributeType: cty.Number, Required: true}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		nb    *tfjson.SchemaBlockType
+		hcl   string
+		label string
+	}{
+		{"single", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeSingle, Block: block}, "object({ name = string, rule = set(object({ port = number })), size = optional(number) })", "single block"},
+		{"required single", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeSingle, MinItems: 1, MaxItems: 1}, "object({})", "single block, required"},
+		{"group", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeGroup}, "object({})", "group block"},
+		{"list", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeList}, "list(object({}))", "list block"},
+		{"list of one", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeList, MaxItems: 1}, "object({})", "list block, max 1"},
+		{"required list of one", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeList, MinItems: 1, MaxItems: 1}, "object({})", "list block, exactly 1, required"},
+		{"required set", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeSet, MinItems: 1}, "set(object({}))", "set block, required"},
+		{"bounded set", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeSet, MinItems: 2, MaxItems: 5}, "set(object({}))", "set block, 2..5 items, required"},
+		{"set of many", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeSet, MinItems: 2}, "set(object({}))", "set block, min 2, required"},
+		{"map", &tfjson.SchemaBlockType{NestingMode: tfjson.SchemaNestingModeMap, MaxItems: 3}, "map(object({}))", "map block, max 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.hcl, HCLType(BlockType(tt.nb)))
+			require.Equal(t, tt.label, BlockLabel(tt.nb))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EntityKind identifies which section of a provider schema an entity belongs to
+type EntityKind string
+
+const (
+	KindResource          EntityKind = "resource"
+	KindDataSource        EntityKind = "data"
+	KindEphemeralResource EntityKind = "ephemeral"
+	KindFunction          EntityKind = "function"
+)
+
+// ParseEntityKind converts a user supplied kind (e.g. "resource", "data-source") into an EntityKind
+func ParseEntityKind(kind string) (EntityKind, error) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "resource", "resources":
+		return KindResource, nil
+	case "data", "data-source", "data_source", "datasource", "data-sources":
+		return KindDataSource, nil
+	case "ephemeral", "ephemeral-resource", "ephemeral_resource":
+		return KindEphemeralResource, nil
+	case "function", "functions", "provider-function":
+		return KindFunction, nil
+	}
+	return "", fmt.Errorf("unknown kind %q (expected resource, data, ephemeral or function)", kind)
+}
+
+// ResolveProviderName finds the full provider address for a short name.
+// It accepts the full address ("registry.terraform.io/hashicorp/aws"),
+// a namespaced name ("hashicorp/aws") or just the type name ("aws").
+func ResolveProviderName(providerSchemas *ProviderSchemas, name string) (string, error) {
+	if providerSchemas == nil || len(providerSchemas.Schemas) == 0 {
+		return "", fmt.Errorf("no provider schemas available")
+	}
+
+	if _, exists := providerSchemas.Schemas[name]; exists {
+		return name, nil
+	}
+
+	var matches []string
+	for fullName := range providerSchemas.Schemas {
+		if strings.HasSuffix(fullName, "/"+name) {
+			matches = append(matches, fullName)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("provider %s not found in schema", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("provider %s is ambiguous: %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// GetEntitySchema looks up a resource, data source or ephemeral resource by kind
+func GetEntitySchema(providerSchemas *ProviderSchemas, providerName string, kind EntityKind, name string) (*Schema, error) {
+	switch kind {
+	case KindResource:
+		return GetResourceSchema(providerSchemas, providerName, name)
+	case KindDataSource:
+		return GetDataSourceSchema(providerSchemas, providerName, name)
+	case KindEphemeralResource:
+		return GetEphemeralResourceSchema(providerSchemas, providerName, name)
+	case KindFunction:
+		return nil, fmt.Errorf("functions do not have a block schema, use GetFunctionSchema")
+	}
+	return nil, fmt.Errorf("unknown kind %q", kind)
+}
@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func loadFixture(t *testing.T) *ProviderSchemas {
+	t.Helper()
+	data, err := os.ReadFile(filepath.FromSlash("../../testdata/schemas/aws_min.json"))
+	require.NoError(t, err)
+	var ps ProviderSchemas
+	require.NoError(t, json.Unmarshal(data, &ps))
+	return &ps
+}
+
+func TestResolveProviderName(t *testing.T) {
+	ps := loadFixture(t)
+
+	for _, name := range []string{"aws", "hashicorp/aws", "registry.terraform.io/hashicorp/aws"} {
+		got, err := ResolveProviderName(ps, name)
+		require.NoError(t, err, name)
+		require.Equal(t, "registry.terraform.io/hashicorp/aws", got)
+	}
+
+	_, err := ResolveProviderName(ps, "azurerm")
+	require.Error(t, err)
+}
+
+func TestWriteSchemaTree(t *testing.T) {
+	ps := loadFixture(t)
+	s, err := GetEntitySchema(ps, "registry.terraform.io/hashicorp/aws", KindResource, "aws_instance")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSchemaTree(&buf, "aws_instance", s))
+
+	out := buf.String()
+	require.Contains(t, out, "aws_instance\n")
+	require.Contains(t, out, "├── ami (string) [required]\n")
+	require.Contains(t, out, "├── tags (map(string)) [optional]\n")
+	require.Contains(t, out, "└── root_block_device [single block]\n")
+	require.Contains(t, out, "    └── volume_type (string) [optional]\n")
+}
+
+func TestWriteSchemaTree_NestedAttributes(t *testing.T) {
+	s := &Schema{Block: &tfjson.SchemaBlock{Attributes: map[string]*tfjson.SchemaAttribute{
+		"name": {AttributeType: cty.String, Required: true},
+		"rules": {Optional: true, AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+			NestingMode: tfjson.SchemaNestingModeList,
+			Attributes: map[string]*tfjson.SchemaAttribute{
+				"port": {AttributeType: cty.Number, Required: true},
+				"target": {Optional: true, AttributeNestedType: &tfjson.SchemaNestedAttributeType{
+					NestingMode: tfjson.SchemaNestingModeSingle,
+					Attributes: map[string]*tfjson.SchemaAttribute{
+						"id": {AttributeType: cty.String, Computed: true},
+					},
+				}},
+			},
+		}},
+	}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSchemaTree(&buf, "example_firewall", s))
+	require.Equal(t, `example_firewall
+├── name (string) [required]
+└── rules (list(object)) [optional]
+    ├── port (number) [required]
+    └── target (object) [optional]
+        └── id (string) [computed]
+`, buf.String())
+}
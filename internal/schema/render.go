@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Tree glyphs match the ones used by the TUI schema tree
+const (
+	treeFork   = "├── "
+	treeLeaf   = "└── "
+	treeBranch = "│   "
+	treeIndent = "    "
+)
+
+// WriteSchemaTree renders an entity schema as a plain-text tree.
+// Attributes are listed before nested blocks, both sorted by name.
+func WriteSchemaTree(w io.Writer, name string, s *Schema) error {
+	if s == nil || s.Block == nil {
+		return fmt.Errorf("schema for %s has no block", name)
+	}
+	if _, err := fmt.Fprintln(w, name); err != nil {
+		return err
+	}
+	return writeBlock(w, s.Block, "")
+}
+
+func writeBlock(w io.Writer, block *tfjson.SchemaBlock, prefix string) error {
+	attrNames := make([]string, 0, len(block.Attributes))
+	for name := range block.Attributes {
+		attrNames = append(attrNames, name)
+	}
+	sort.Strings(attrNames)
+
+	blockNames := make([]string, 0, len(block.NestedBlocks))
+	for name := range block.NestedBlocks {
+		blockNames = append(blockNames, name)
+	}
+	sort.Strings(blockNames)
+
+	total := len(attrNames) + len(blockNames)
+	idx := 0
+	for _, name := range attrNames {
+		idx++
+		connector, childPrefix := treeFork, prefix+treeBranch
+		if idx == total {
+			connector, childPrefix = treeLeaf, prefix+treeIndent
+		}
+		attr := block.Attributes[name]
+		if _, err := fmt.Fprintf(w, "%s%s%s\n", prefix, connector, attributeLabel(name, attr)); err != nil {
+			return err
+		}
+		// Nested attributes list their own attributes below them, like nested blocks
+		if attr.AttributeNestedType == nil {
+			continue
+		}
+		if err := writeBlock(w, &tfjson.SchemaBlock{Attributes: attr.AttributeNestedType.Attributes}, childPrefix); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range blockNames {
+		idx++
+		connector, childPrefix := treeFork, prefix+treeBranch
+		if idx == total {
+			connector, childPrefix = treeLeaf, prefix+treeIndent
+		}
+		nested := block.NestedBlocks[name]
+		if _, err := fmt.Fprintf(w, "%s%s%s [%s]\n", prefix, connector, name, BlockLabel(nested)); err != nil {
+			return err
+		}
+		if nested == nil || nested.Block == nil {
+			continue
+		}
+		if err := writeBlock(w, nested.Block, childPrefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attributeLabel renders "name (type) [status]" for an attribute. The type of a nested
+// attribute is built from the attributes below it.
+func attributeLabel(name string, attr *tfjson.SchemaAttribute) string {
+	label := name
+	if ty := AttributeType(attr); ty != cty.NilType {
+		label += fmt.Sprintf(" (%s)", TypeLabel(ty))
+	}
+	switch {
+	case attr.Required:
+		label += " [required]"
+	case attr.Optional && attr.Computed:
+		label += " [optional, computed]"
+	case attr.Optional:
+		label += " [optional]"
+	case attr.Computed:
+		label += " [computed]"
+	}
+	if attr.Sensitive {
+		label += " [sensitive]"
+	}
+	if attr.Deprecated {
+		label += " [deprecated]"
+	}
+	return label
+}
+
+// BlockLabel describes how a nested block is written: its nesting mode and how many
+// times it may or must appear, e.g. "list block, max 1" or "set block, min 2, required"
+func BlockLabel(nb *tfjson.SchemaBlockType) string {
+	if nb == nil {
+		return "block"
+	}
+	mode := string(nb.NestingMode)
+	if mode == "" {
+		mode = string(tfjson.SchemaNestingModeSingle)
+	}
+	parts := []string{mode + " block"}
+
+	// Single and group blocks appear at most once whatever their limits say
+	if nb.NestingMode != tfjson.SchemaNestingModeSingle && nb.NestingMode != tfjson.SchemaNestingModeGroup {
+		switch {
+		case nb.MinItems > 0 && nb.MinItems == nb.MaxItems:
+			parts = append(parts, fmt.Sprintf("exactly %d", nb.MinItems))
+		case nb.MinItems > 0 && nb.MaxItems > 0:
+			parts = append(parts, fmt.Sprintf("%d..%d items", nb.MinItems, nb.MaxItems))
+		case nb.MinItems > 1:
+			parts = append(parts, fmt.Sprintf("min %d", nb.MinItems))
+		case nb.MaxItems > 0:
+			parts = append(parts, fmt.Sprintf("max %d", nb.MaxItems))
+		}
+	}
+	if nb.MinItems > 0 {
+		parts = append(parts, "required")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WriteFunctionSignature renders a provider function signature as plain text
+func WriteFunctionSignature(w io.Writer, name string, fn *FunctionSignature) error {
+	if fn == nil {
+		return fmt.Errorf("function %s has no signature", name)
+	}
+
+	var params []string
+	for _, p := range fn.Parameters {
+		params = append(params, fmt.Sprintf("%s %s", p.Name, p.Type.FriendlyName()))
+	}
+	if fn.VariadicParameter != nil {
+		params = append(params, fmt.Sprintf("%s ...%s", fn.VariadicParameter.Name, fn.VariadicParameter.Type.FriendlyName()))
+	}
+
+	if _, err := fmt.Fprintf(w, "%s(%s) %s\n", name, strings.Join(params, ", "), fn.ReturnType.FriendlyName()); err != nil {
+		return err
+	}
+	if fn.Summary != "" {
+		if _, err := fmt.Fprintf(w, "\n%s\n", fn.Summary); err != nil {
+			return err
+		}
+	}
+	if fn.Description != "" {
+		if _, err := fmt.Fprintf(w, "\n%s\n", fn.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
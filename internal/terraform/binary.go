@@ -1,7 +1,10 @@
 package terraform
 
 import (
+	"fmt"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 )
@@ -30,6 +33,9 @@ type TerraformInfo struct {
 	Binary   string
 	Tool     string // "terraform" or "tofu"
 	Registry string
+
+	// Installation overrides where providers are installed from (--mirror, --plugin-cache-dir)
+	Installation ProviderInstallation `json:"-"`
 }
 
 // FindTerraformBinary detects available terraform tools and returns info for the preferred one
@@ -86,6 +92,50 @@ func FindTerraformBinaryWithPreference(preference string) TerraformInfo {
 	}
 }
 
+// ResolveTerraformInfo selects the tool to run from an explicit binary path and/or tool name.
+// An explicit binary wins; its tool is taken from the tool name or inferred from the file name.
+// A tool name alone must be available in PATH. With neither, the default detection is used.
+func ResolveTerraformInfo(tool, binary string) (TerraformInfo, error) {
+	if tool != "" && tool != "terraform" && tool != "tofu" {
+		return TerraformInfo{}, fmt.Errorf("unknown tool %q (expected terraform or tofu)", tool)
+	}
+
+	if binary != "" {
+		path, err := exec.LookPath(binary)
+		if err != nil {
+			return TerraformInfo{}, fmt.Errorf("binary %s not found: %w", binary, err)
+		}
+		if tool == "" {
+			tool = "terraform"
+			if strings.Contains(filepath.Base(path), "tofu") {
+				tool = "tofu"
+			}
+		}
+		return TerraformInfo{
+			Binary:   path,
+			Tool:     tool,
+			Registry: registryForTool(tool),
+		}, nil
+	}
+
+	if tool != "" {
+		if _, err := exec.LookPath(tool); err != nil {
+			return TerraformInfo{}, fmt.Errorf("%s not found in PATH", tool)
+		}
+		return FindTerraformBinaryWithPreference(tool), nil
+	}
+
+	return FindTerraformBinary(), nil
+}
+
+// registryForTool returns the default provider registry host for a tool
+func registryForTool(tool string) string {
+	if tool == "tofu" {
+		return "registry.opentofu.org"
+	}
+	return "registry.terraform.io"
+}
+
 // GetAvailableTools returns a list of available terraform tools
 func GetAvailableTools() []string {
 	var tools []string
@@ -0,0 +1,34 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTerraformInfo_ExplicitBinary(t *testing.T) {
+	dir := t.TempDir()
+	tofuPath := filepath.Join(dir, "tofu")
+	require.NoError(t, os.WriteFile(tofuPath, []byte("#!/bin/sh\n"), 0755))
+
+	// Tool is inferred from the binary name
+	info, err := ResolveTerraformInfo("", tofuPath)
+	require.NoError(t, err)
+	require.Equal(t, tofuPath, info.Binary)
+	require.Equal(t, "tofu", info.Tool)
+	require.Equal(t, "registry.opentofu.org", info.Registry)
+
+	// An explicit tool overrides inference
+	info, err = ResolveTerraformInfo("terraform", tofuPath)
+	require.NoError(t, err)
+	require.Equal(t, "terraform", info.Tool)
+	require.Equal(t, "registry.terraform.io", info.Registry)
+
+	_, err = ResolveTerraformInfo("", filepath.Join(dir, "missing"))
+	require.Error(t, err)
+
+	_, err = ResolveTerraformInfo("opentofu", "")
+	require.Error(t, err)
+}
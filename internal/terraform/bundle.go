@@ -0,0 +1,157 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+)
+
+// Bundles share the cache file envelope: a JSON header line carrying the provenance,
+// followed by the gzip-compressed SchemaWithVersionInfo. They are meant to be copied
+// between machines, e.g. from CI to a laptop without registry access.
+const (
+	bundleFormatName    = "provider-explorer-bundle"
+	bundleFormatVersion = 1
+)
+
+// BundleProvenance records where the schemas of a bundle came from
+type BundleProvenance struct {
+	Tool        string           `json:"tool"`
+	ToolVersion string           `json:"tool_version,omitempty"`
+	Platform    string           `json:"platform"` // <os>_<arch> of the packing machine
+	Providers   []BundleProvider `json:"providers"`
+}
+
+// BundleProvider is a provider whose schema a bundle holds
+type BundleProvider struct {
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+	// Constraints and Hashes are copied from the lockfile of the packed workspace
+	Constraints string   `json:"constraints,omitempty"`
+	Hashes      []string `json:"hashes,omitempty"`
+}
+
+// Bundle is a portable archive of the schemas of a workspace
+type Bundle struct {
+	Provenance BundleProvenance
+	CreatedAt  time.Time
+	Schema     *SchemaWithVersionInfo
+}
+
+// NewBundle packs the schemas fetched for a working directory together with their
+// provenance. Provider versions and hashes come from the workspace's lockfile when it
+// has one, and otherwise from the versions reported alongside the schemas.
+func NewBundle(workingDir string, schemaWithVersion *SchemaWithVersionInfo) (*Bundle, error) {
+	if schemaWithVersion.Schemas == nil || len(schemaWithVersion.Schemas.Schemas) == 0 {
+		return nil, errors.New("no provider schemas to pack")
+	}
+
+	var locked map[string]LockedProvider
+	if _, err := os.Stat(filepath.Join(workingDir, ".terraform.lock.hcl")); err == nil {
+		if locked, err = ReadLockfile(workingDir); err != nil {
+			return nil, err
+		}
+	}
+
+	versionInfo := schemaWithVersion.VersionInfo
+	if versionInfo == nil {
+		versionInfo = &schema.VersionOutput{}
+	}
+	provenance := BundleProvenance{
+		Tool:        schemaWithVersion.TfInfo.Tool,
+		ToolVersion: versionInfo.Version,
+		Platform:    runtime.GOOS + "_" + runtime.GOARCH,
+	}
+	for source := range schemaWithVersion.Schemas.Schemas {
+		p := BundleProvider{Source: source, Version: versionInfo.ProviderSelections[source]}
+		if lock, ok := locked[source]; ok {
+			p.Version = lock.Version
+			p.Constraints = lock.Constraints
+			p.Hashes = lock.Hashes
+		}
+		provenance.Providers = append(provenance.Providers, p)
+	}
+	sort.Slice(provenance.Providers, func(i, j int) bool {
+		return provenance.Providers[i].Source < provenance.Providers[j].Source
+	})
+
+	// The binary path only makes sense on the packing machine
+	packed := *schemaWithVersion
+	packed.TfInfo.Binary = ""
+	packed.FromCache = false
+
+	return &Bundle{
+		Provenance: provenance,
+		CreatedAt:  time.Now().UTC(),
+		Schema:     &packed,
+	}, nil
+}
+
+// WriteBundle writes a bundle to path
+func WriteBundle(path string, b *Bundle) error {
+	provenance := b.Provenance
+	header := cacheHeader{
+		Format:    bundleFormatName,
+		CreatedAt: b.CreatedAt,
+		Bundle:    &provenance,
+	}
+	return writeCacheFile(path, header, b.Schema)
+}
+
+// ReadBundle reads a bundle written by WriteBundle and verifies its checksum
+func ReadBundle(path string) (*Bundle, error) {
+	var schemaWithVersion SchemaWithVersionInfo
+	header, err := readCacheFile(path, &schemaWithVersion)
+	if errors.Is(err, errLegacyCacheFormat) || (err == nil && header.Format != bundleFormatName) {
+		return nil, fmt.Errorf("%s is not a schema bundle", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if header.Bundle == nil {
+		return nil, fmt.Errorf("bundle %s records no provenance", path)
+	}
+	if schemaWithVersion.Schemas == nil {
+		return nil, fmt.Errorf("bundle %s holds no schemas", path)
+	}
+	return &Bundle{
+		Provenance: *header.Bundle,
+		CreatedAt:  header.CreatedAt,
+		Schema:     &schemaWithVersion,
+	}, nil
+}
+
+// Unpack stores the bundled provider schemas in the per-provider cache, so workspaces
+// locking the same provider versions open without init or registry access. Providers
+// packed without a version cannot be keyed and are skipped. It returns the cache keys
+// written, in source@version form.
+func (b *Bundle) Unpack() ([]string, error) {
+	selections := make(map[string]string, len(b.Provenance.Providers))
+	for _, p := range b.Provenance.Providers {
+		if p.Version != "" {
+			selections[p.Source] = p.Version
+		}
+	}
+	tfInfo := TerraformInfo{Tool: b.Provenance.Tool}
+	keys := providerCacheKeys(selections, tfInfo, &schema.VersionOutput{Version: b.Provenance.ToolVersion})
+
+	var written []string
+	for source, version := range keys {
+		ps, ok := b.Schema.Schemas.Schemas[source]
+		if !ok {
+			continue
+		}
+		if err := writeProviderToCache(source, version, ps); err != nil {
+			return written, err
+		}
+		written = append(written, source+"@"+version)
+	}
+	sort.Strings(written)
+	return written, nil
+}
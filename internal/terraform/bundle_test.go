@@ -0,0 +1,79 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_RoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	fetching := writeScript(t, `case "$1" in
+version) echo '{"terraform_version":"1.10.5"}' ;;
+providers) echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/aws":{"resource_schemas":{"aws_s3_bucket":{"version":0,"block":{}}}},"terraform.io/builtin/terraform":{"resource_schemas":{"terraform_data":{"version":0,"block":{}}}}}}' ;;
+esac
+`)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), []byte(testLockfile), 0644))
+	tfInfo := TerraformInfo{Binary: fetching, Tool: "terraform"}
+
+	fetched, err := FetchAllProviderSchemas(dir, tfInfo)
+	require.NoError(t, err)
+	b, err := NewBundle(dir, fetched)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "schemas.bundle")
+	require.NoError(t, WriteBundle(path, b))
+	_, err = ClearProviderCache()
+	require.NoError(t, err)
+
+	read, err := ReadBundle(path)
+	require.NoError(t, err)
+	require.Equal(t, "terraform", read.Provenance.Tool)
+	require.Equal(t, "1.10.5", read.Provenance.ToolVersion)
+	require.Equal(t, []BundleProvider{
+		{Source: "registry.terraform.io/hashicorp/aws", Version: "6.0.0", Constraints: "~> 6.0", Hashes: []string{"h1:abc="}},
+		{Source: "terraform.io/builtin/terraform"},
+	}, read.Provenance.Providers)
+	require.Empty(t, read.Schema.TfInfo.Binary)
+	require.Contains(t, read.Schema.Schemas.Schemas["registry.terraform.io/hashicorp/aws"].ResourceSchemas, "aws_s3_bucket")
+
+	// Bundles also open wherever saved schema files are accepted
+	fromFile, err := ReadProviderSchemaFile(path)
+	require.NoError(t, err)
+	require.Len(t, fromFile.Schemas.Schemas, 2)
+
+	// Unpacking lets a workspace with the same lockfile open without the tool
+	keys, err := read.Unpack()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"registry.terraform.io/hashicorp/aws@6.0.0",
+		"terraform.io/builtin/terraform@terraform-1.10.5",
+	}, keys)
+
+	failing := writeScript(t, `case "$1" in
+version) echo '{"terraform_version":"1.10.5"}' ;;
+*) echo "providers schema must not run" >&2; exit 1 ;;
+esac
+`)
+	other := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(other, ".terraform.lock.hcl"), []byte(testLockfile), 0644))
+	result, err := FetchAllProviderSchemas(other, TerraformInfo{Binary: failing, Tool: "terraform"})
+	require.NoError(t, err)
+	require.True(t, result.FromCache)
+	require.Contains(t, result.Schemas.Schemas, "terraform.io/builtin/terraform")
+}
+
+func TestReadBundle_RejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"format_version":"1.0"}`), 0644))
+	_, err := ReadBundle(path)
+	require.ErrorContains(t, err, "is not a schema bundle")
+
+	require.NoError(t, writeCacheFile(path, cacheHeader{Provider: "registry.terraform.io/hashicorp/aws@6.0.0"}, struct{}{}))
+	_, err = ReadBundle(path)
+	require.ErrorContains(t, err, "is not a schema bundle")
+}
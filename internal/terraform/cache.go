@@ -3,13 +3,17 @@ package terraform
 import (
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
 )
 
 // ProviderSpec represents a provider specification for cache key generation
@@ -18,19 +22,6 @@ type ProviderSpec struct {
 	Constraints string
 }
 
-// getLockfileHash reads the .terraform.lock.hcl file and returns its SHA256 hash
-func getLockfileHash(workingDir string) (string, error) {
-	lockfilePath := filepath.Join(workingDir, ".terraform.lock.hcl")
-
-	data, err := os.ReadFile(lockfilePath)
-	if err != nil {
-		return "", err
-	}
-
-	hash := sha256.Sum256(data)
-	return fmt.Sprintf("%x", hash), nil
-}
-
 // getProviderSpecs extracts provider requirements from terraform configuration
 func getProviderSpecs(workingDir string) ([]ProviderSpec, error) {
 	module, diags := tfconfig.LoadModule(workingDir)
@@ -55,19 +46,90 @@ func getProviderSpecs(workingDir string) ([]ProviderSpec, error) {
 	return specs, nil
 }
 
-// generateProviderSpecHash creates a hash from provider specifications
-func generateProviderSpecHash(specs []ProviderSpec) string {
-	var parts []string
-	for _, spec := range specs {
-		part := fmt.Sprintf("%s@%s", spec.Source, spec.Constraints)
-		parts = append(parts, part)
+// CacheIdentity is everything that determines the provider schemas of a workspace. Its
+// hash is the cache key, and it is recorded in the cache file so that an entry written
+// for another workspace is refused instead of shown.
+type CacheIdentity struct {
+	WorkingDir  string `json:"working_dir"`
+	Tool        string `json:"tool"`
+	ToolVersion string `json:"tool_version"`
+	DataDir     string `json:"data_dir"`
+	// Providers maps each provider source to its locked version or, without a
+	// lockfile, to the version constraints from required_providers
+	Providers map[string]string `json:"providers,omitempty"`
+}
+
+// NewCacheIdentity builds the cache identity of a workspace for the given tool
+func NewCacheIdentity(workingDir string, tfInfo TerraformInfo, toolVersion string) (CacheIdentity, error) {
+	absDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return CacheIdentity{}, fmt.Errorf("failed to resolve %s: %w", workingDir, err)
+	}
+
+	id := CacheIdentity{
+		WorkingDir:  absDir,
+		Tool:        tfInfo.Tool,
+		ToolVersion: toolVersion,
+		DataDir:     DataDir(absDir),
+	}
+
+	if selections, err := ReadLockfileSelections(absDir); err == nil {
+		id.Providers = selections
+	} else if specs, err := getProviderSpecs(absDir); err == nil && len(specs) > 0 {
+		id.Providers = make(map[string]string, len(specs))
+		for _, spec := range specs {
+			id.Providers[spec.Source] = spec.Constraints
+		}
 	}
 
-	combined := strings.Join(parts, "|")
-	hash := sha256.Sum256([]byte(combined))
+	return id, nil
+}
+
+// Key returns the cache key derived from the identity
+func (id CacheIdentity) Key() string {
+	// encoding/json sorts map keys, so equal identities always hash the same
+	data, _ := json.Marshal(id)
+	hash := sha256.Sum256(data)
 	return fmt.Sprintf("%x", hash)
 }
 
+// mismatch names the first field in which a recorded identity differs from id
+func (id CacheIdentity) mismatch(recorded CacheIdentity) string {
+	switch {
+	case id.WorkingDir != recorded.WorkingDir:
+		return fmt.Sprintf("written for %s", recorded.WorkingDir)
+	case id.Tool != recorded.Tool:
+		return fmt.Sprintf("written by %s", recorded.Tool)
+	case id.ToolVersion != recorded.ToolVersion:
+		return fmt.Sprintf("written by %s %s", recorded.Tool, recorded.ToolVersion)
+	case id.DataDir != recorded.DataDir:
+		return fmt.Sprintf("written for data directory %s", recorded.DataDir)
+	case !maps.Equal(id.Providers, recorded.Providers):
+		return "written for different provider versions"
+	}
+	return ""
+}
+
+// DataDir returns the data directory terraform uses for a working directory, honouring
+// TF_DATA_DIR. It is absolute when workingDir is.
+func DataDir(workingDir string) string {
+	dir := os.Getenv("TF_DATA_DIR")
+	if dir == "" {
+		dir = ".terraform"
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(workingDir, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// legacyWorkspaceCacheFile is the plain JSON form workspace entries had before the
+// envelope format
+type legacyWorkspaceCacheFile struct {
+	Identity *CacheIdentity `json:"identity,omitempty"`
+	SchemaWithVersionInfo
+}
+
 func getCacheDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -76,90 +138,194 @@ func getCacheDir() (string, error) {
 	return filepath.Join(homeDir, ".resource-cache"), nil
 }
 
-func getCacheFilePath(workingDir string) (string, error) {
+func getCacheFilePath(id CacheIdentity) (string, error) {
 	cacheDir, err := getCacheDir()
 	if err != nil {
 		return "", err
 	}
+	return filepath.Join(cacheDir, cacheFilePrefix+id.Key()+cacheFileSuffix), nil
+}
 
-	var cacheKey string
-
-	// Strategy 1: Try to use lockfile hash (most reliable)
-	if lockfileHash, err := getLockfileHash(workingDir); err == nil {
-		cacheKey = lockfileHash
-		// fmt.Fprintf(os.Stderr, "Using lockfile-based cache key\n")
-	} else {
-		// Strategy 2: Use provider specifications from terraform config
-		if specs, err := getProviderSpecs(workingDir); err == nil && len(specs) > 0 {
-			cacheKey = generateProviderSpecHash(specs)
-			fmt.Fprintf(os.Stderr, "Using provider-spec-based cache key\n")
+// HasValidProviderCache checks if a valid cache exists for the given working directory,
+// either as a whole document or as per-provider entries for every locked provider. Only
+// cache headers are read.
+func HasValidProviderCache(workingDir string, tfInfo TerraformInfo) bool {
+	if selections, err := ReadLockfileSelections(workingDir); err == nil && len(selections) > 0 {
+		if providersCached(selections) {
+			return true
 		}
 	}
 
-	filename := fmt.Sprintf("provider_schemas_%s.json", cacheKey)
-	return filepath.Join(cacheDir, filename), nil
-}
-
-// HasValidProviderCache checks if a valid cache exists for the given working directory
-func HasValidProviderCache(workingDir string) bool {
-	cachePath, err := getCacheFilePath(workingDir)
+	var toolVersion string
+	if versionInfo, err := DetectVersion(tfInfo, workingDir); err == nil {
+		toolVersion = versionInfo.Version
+	}
+	id, err := NewCacheIdentity(workingDir, tfInfo, toolVersion)
 	if err != nil {
 		return false
 	}
-
-	// Check if cache file exists
-	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
+	cachePath, err := getCacheFilePath(id)
+	if err != nil {
 		return false
 	}
-
-	// Try to read and parse the cache to ensure it's valid
-	_, err = ReadProviderSchemaFromCache(workingDir)
+	_, err = readWorkspaceHeader(id, cachePath)
 	return err == nil
 }
 
-func ReadProviderSchemaFromCache(workingDir string) (*SchemaWithVersionInfo, error) {
-	cachePath, err := getCacheFilePath(workingDir)
+// readWorkspaceHeader reads the header of a workspace entry and checks its identity
+func readWorkspaceHeader(id CacheIdentity, cachePath string) (*cacheHeader, error) {
+	header, err := readCacheHeader(cachePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("cache file not found: %s", cachePath)
+	}
 	if err != nil {
 		return nil, err
 	}
-
-	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("cache file not found: %s", cachePath)
+	if header.Identity == nil {
+		return nil, fmt.Errorf("cache file %s has no recorded identity", cachePath)
+	}
+	if reason := id.mismatch(*header.Identity); reason != "" {
+		return nil, fmt.Errorf("refusing cache file %s: %s", cachePath, reason)
 	}
+	return header, nil
+}
 
-	data, err := os.ReadFile(cachePath)
+// ReadProviderSchemaFromCache returns the cached schema document for a workspace. Entries
+// whose recorded identity differs from id are refused; legacy entries are migrated to the
+// current format when they record a matching identity and discarded otherwise.
+func ReadProviderSchemaFromCache(id CacheIdentity) (*SchemaWithVersionInfo, error) {
+	cachePath, err := getCacheFilePath(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read cache file: %w", err)
+		return nil, err
 	}
 
-	var schemaWithVersion SchemaWithVersionInfo
-	if err := json.Unmarshal(data, &schemaWithVersion); err != nil {
-		return nil, fmt.Errorf("failed to parse cached schema: %w", err)
+	if _, err := readWorkspaceHeader(id, cachePath); err != nil {
+		if errors.Is(err, errLegacyCacheFormat) {
+			return migrateWorkspaceCache(id, cachePath, cachePath)
+		}
+		if legacyPath := legacyCachePath(cachePath); fileExists(legacyPath) {
+			return migrateWorkspaceCache(id, legacyPath, cachePath)
+		}
+		return nil, err
 	}
 
+	var schemaWithVersion SchemaWithVersionInfo
+	if _, err := readCacheFile(cachePath, &schemaWithVersion); err != nil {
+		return nil, err
+	}
 	return &schemaWithVersion, nil
 }
 
-func WriteProviderSchemaToCache(workingDir string, schemaWithVersion *SchemaWithVersionInfo) error {
-	cachePath, err := getCacheFilePath(workingDir)
-	if err != nil {
-		return err
+// migrateWorkspaceCache rewrites the legacy workspace entry at legacyPath in the current
+// format at cachePath
+func migrateWorkspaceCache(id CacheIdentity, legacyPath, cachePath string) (*SchemaWithVersionInfo, error) {
+	var legacy legacyWorkspaceCacheFile
+	data, err := os.ReadFile(legacyPath)
+	if err == nil {
+		err = json.Unmarshal(data, &legacy)
+	}
+	if err != nil || legacy.Identity == nil || id.mismatch(*legacy.Identity) != "" || legacy.Schemas == nil {
+		// Without a matching identity there is no telling whose schemas these are
+		os.Remove(legacyPath)
+		os.Remove(metadataPath(legacyPath))
+		return nil, fmt.Errorf("discarded legacy cache file %s", legacyPath)
 	}
 
-	cacheDir := filepath.Dir(cachePath)
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
+	meta, err := readCacheMetadata(legacyPath)
+	if err != nil {
+		meta = newCacheMetadata(id, &legacy.SchemaWithVersionInfo)
+	}
+	if err := writeWorkspaceCache(cachePath, id, meta, &legacy.SchemaWithVersionInfo); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to migrate cache file: %v\n", err)
+	} else if legacyPath != cachePath {
+		os.Remove(legacyPath)
+		os.Remove(metadataPath(legacyPath))
 	}
+	return &legacy.SchemaWithVersionInfo, nil
+}
 
-	data, err := json.MarshalIndent(schemaWithVersion, "", "  ")
+// WriteProviderSchemaToCache stores the schema document of a workspace together with its identity
+func WriteProviderSchemaToCache(id CacheIdentity, schemaWithVersion *SchemaWithVersionInfo) error {
+	cachePath, err := getCacheFilePath(id)
 	if err != nil {
-		return fmt.Errorf("failed to marshal schema: %w", err)
+		return err
 	}
 
-	if err := os.WriteFile(cachePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write cache file: %w", err)
+	if err := writeWorkspaceCache(cachePath, id, newCacheMetadata(id, schemaWithVersion), schemaWithVersion); err != nil {
+		return err
 	}
 
 	fmt.Fprintf(os.Stderr, "Provider schemas cached at: %s\n", cachePath)
 	return nil
 }
+
+func writeWorkspaceCache(cachePath string, id CacheIdentity, meta *CacheMetadata, schemaWithVersion *SchemaWithVersionInfo) error {
+	header := cacheHeader{
+		CreatedAt: meta.CreatedAt,
+		Identity:  &id,
+		Metadata:  meta,
+	}
+	if err := writeCacheFile(cachePath, header, schemaWithVersion); err != nil {
+		return err
+	}
+	// Metadata now lives in the header; drop the sidecar of a legacy entry
+	if err := os.Remove(metadataPath(cachePath)); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return nil
+}
+
+// ReadProviderSchemaFile loads schemas from a file that is either a cache entry (workspace
+// or single provider, current or legacy format), a bundle or a raw `providers schema -json`
+// document.
+func ReadProviderSchemaFile(path string) (*SchemaWithVersionInfo, error) {
+	header, err := readCacheHeader(path)
+	switch {
+	case err == nil && header.Provider != "":
+		var ps schema.ProviderSchema
+		if _, err := readCacheFile(path, &ps); err != nil {
+			return nil, err
+		}
+		source, _ := splitProviderKey(header.Provider)
+		return &SchemaWithVersionInfo{Schemas: &schema.ProviderSchemas{
+			FormatVersion: "1.0",
+			Schemas:       map[string]*schema.ProviderSchema{source: &ps},
+		}}, nil
+	case err == nil:
+		var schemaWithVersion SchemaWithVersionInfo
+		if _, err := readCacheFile(path, &schemaWithVersion); err != nil {
+			return nil, err
+		}
+		return &schemaWithVersion, nil
+	case !errors.Is(err, errLegacyCacheFormat):
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if _, ok := probe["schemas"]; ok {
+		var schemaWithVersion SchemaWithVersionInfo
+		if err := json.Unmarshal(data, &schemaWithVersion); err != nil {
+			return nil, fmt.Errorf("failed to parse cached schema %s: %w", path, err)
+		}
+		return &schemaWithVersion, nil
+	}
+
+	if _, ok := probe["provider_schemas"]; ok {
+		var ps schema.ProviderSchemas
+		if err := json.Unmarshal(data, &ps); err != nil {
+			return nil, fmt.Errorf("failed to parse provider schemas %s: %w", path, err)
+		}
+		return &SchemaWithVersionInfo{Schemas: &ps}, nil
+	}
+
+	return nil, fmt.Errorf("%s is neither a schema cache file nor a providers schema document", path)
+}
@@ -0,0 +1,117 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+)
+
+func TestCacheIdentity_DoesNotCollideWithoutProviders(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tfInfo := TerraformInfo{Binary: "terraform", Tool: "terraform"}
+
+	// Neither workspace has a lockfile or required_providers
+	first, err := NewCacheIdentity(t.TempDir(), tfInfo, "1.10.5")
+	require.NoError(t, err)
+	second, err := NewCacheIdentity(t.TempDir(), tfInfo, "1.10.5")
+	require.NoError(t, err)
+	require.NotEqual(t, first.Key(), second.Key())
+
+	otherTool, err := NewCacheIdentity(first.WorkingDir, TerraformInfo{Binary: "tofu", Tool: "tofu"}, "1.10.5")
+	require.NoError(t, err)
+	require.NotEqual(t, first.Key(), otherTool.Key())
+
+	t.Setenv("TF_DATA_DIR", "alt-data")
+	otherDataDir, err := NewCacheIdentity(first.WorkingDir, tfInfo, "1.10.5")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(first.WorkingDir, "alt-data"), otherDataDir.DataDir)
+	require.NotEqual(t, first.Key(), otherDataDir.Key())
+
+	require.NoError(t, WriteProviderSchemaToCache(first, &SchemaWithVersionInfo{
+		Schemas: &schema.ProviderSchemas{FormatVersion: "1.0"},
+		TfInfo:  tfInfo,
+	}))
+	_, err = ReadProviderSchemaFromCache(first)
+	require.NoError(t, err)
+	_, err = ReadProviderSchemaFromCache(second)
+	require.Error(t, err)
+}
+
+func TestReadProviderSchemaFromCache_RefusesMismatchedIdentity(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	tfInfo := TerraformInfo{Binary: "terraform", Tool: "terraform"}
+
+	id, err := NewCacheIdentity(t.TempDir(), tfInfo, "1.10.5")
+	require.NoError(t, err)
+	other, err := NewCacheIdentity(t.TempDir(), tfInfo, "1.10.5")
+	require.NoError(t, err)
+
+	// Write another workspace's entry under this workspace's key
+	require.NoError(t, WriteProviderSchemaToCache(other, &SchemaWithVersionInfo{Schemas: &schema.ProviderSchemas{FormatVersion: "1.0"}}))
+	otherPath, err := getCacheFilePath(other)
+	require.NoError(t, err)
+	path, err := getCacheFilePath(id)
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(otherPath, path))
+
+	_, err = ReadProviderSchemaFromCache(id)
+	require.ErrorContains(t, err, "written for "+other.WorkingDir)
+
+	// Legacy entries without a recorded identity are discarded
+	require.NoError(t, os.WriteFile(path, []byte(`{"schemas":{"format_version":"1.0"}}`), 0644))
+	_, err = ReadProviderSchemaFromCache(id)
+	require.ErrorContains(t, err, "discarded legacy cache file")
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestCacheFile_MigratesLegacyAndDetectsCorruption(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tfInfo := TerraformInfo{Binary: writeScript(t, `echo '{"terraform_version":"1.10.5"}'`), Tool: "terraform"}
+
+	id, err := NewCacheIdentity(t.TempDir(), tfInfo, "1.10.5")
+	require.NoError(t, err)
+	path, err := getCacheFilePath(id)
+	require.NoError(t, err)
+
+	// A plain JSON entry with a matching identity is rewritten in the envelope format
+	legacy, err := json.MarshalIndent(legacyWorkspaceCacheFile{
+		Identity:              &id,
+		SchemaWithVersionInfo: SchemaWithVersionInfo{Schemas: &schema.ProviderSchemas{FormatVersion: "1.0"}, TfInfo: tfInfo},
+	}, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	legacyPath := legacyCachePath(path)
+	require.NoError(t, os.WriteFile(legacyPath, legacy, 0644))
+
+	_, err = ReadProviderSchemaFromCache(id)
+	require.NoError(t, err)
+	require.False(t, fileExists(legacyPath))
+	header, err := readCacheHeader(path)
+	require.NoError(t, err)
+	require.Equal(t, cacheFormatVersion, header.Version)
+	require.Equal(t, id.WorkingDir, header.Metadata.SourceDir)
+	require.True(t, HasValidProviderCache(id.WorkingDir, tfInfo))
+
+	// Flipping a byte of the compressed payload is caught by the checksum or gzip CRC,
+	// while the header alone still looks valid
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)-10] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	_, err = readCacheHeader(path)
+	require.NoError(t, err)
+	_, err = ReadProviderSchemaFromCache(id)
+	require.ErrorContains(t, err, "corrupt cache file")
+
+	// No temporary files are left next to the entry
+	names, err := filepath.Glob(filepath.Join(filepath.Dir(path), ".tmp-*"))
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
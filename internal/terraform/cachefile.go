@@ -0,0 +1,191 @@
+package terraform
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache files are a single JSON header line followed by the gzip-compressed JSON
+// payload. The header carries everything needed to decide whether an entry is usable,
+// so validity checks never decompress or parse the payload.
+const (
+	cacheFormatName    = "provider-explorer-cache"
+	cacheFormatVersion = 2
+	cacheEncoding      = "gzip"
+
+	// maxCacheHeaderSize bounds the header line so a corrupt file cannot make us
+	// read megabytes looking for a newline
+	maxCacheHeaderSize = 64 * 1024
+)
+
+// envelopeVersions lists the file formats sharing the envelope layout and the version of
+// each that we read and write
+var envelopeVersions = map[string]int{
+	cacheFormatName:  cacheFormatVersion,
+	bundleFormatName: bundleFormatVersion,
+}
+
+// errLegacyCacheFormat marks a file written before the envelope format was introduced
+var errLegacyCacheFormat = errors.New("cache file uses a legacy format")
+
+// cacheHeader is the first line of every cache file
+type cacheHeader struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	Encoding  string    `json:"encoding"`
+	Checksum  string    `json:"checksum"` // sha256 of the uncompressed payload
+	Size      int64     `json:"size"`     // length of the uncompressed payload
+	CreatedAt time.Time `json:"created_at"`
+
+	// Workspace entries record their identity and provenance
+	Identity *CacheIdentity `json:"identity,omitempty"`
+	Metadata *CacheMetadata `json:"metadata,omitempty"`
+
+	// Provider entries record their source@version key
+	Provider string `json:"provider,omitempty"`
+
+	// Bundles record where their schemas came from
+	Bundle *BundleProvenance `json:"bundle,omitempty"`
+}
+
+// writeCacheFile writes header and payload to path atomically: the data goes to a
+// temporary file in the same directory that is renamed over path once complete. The
+// header is written as a cache entry unless it names another envelope format.
+func writeCacheFile(path string, header cacheHeader, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache payload: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if header.Format == "" {
+		header.Format = cacheFormatName
+	}
+	header.Version = envelopeVersions[header.Format]
+	header.Encoding = cacheEncoding
+	header.Checksum = fmt.Sprintf("sha256:%x", sum)
+	header.Size = int64(len(data))
+	if header.CreatedAt.IsZero() {
+		header.CreatedAt = time.Now().UTC()
+	}
+	headerLine, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache header: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	// Removing after a successful rename fails harmlessly
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	w.Write(headerLine)
+	w.WriteByte('\n')
+	zw := gzip.NewWriter(w)
+	zw.Write(data)
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to compress cache file: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// readHeader reads and validates the header line. Files from before the envelope
+// format, and files in no envelope format at all, yield errLegacyCacheFormat.
+func readHeader(r *bufio.Reader) (*cacheHeader, error) {
+	line, err := r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return nil, errLegacyCacheFormat
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	var header cacheHeader
+	if err := json.Unmarshal(bytes.TrimSpace(line), &header); err != nil || envelopeVersions[header.Format] == 0 {
+		// Legacy files are plain (possibly indented) JSON documents
+		return nil, errLegacyCacheFormat
+	}
+	if header.Version != envelopeVersions[header.Format] {
+		return nil, fmt.Errorf("unsupported %s format version %d", header.Format, header.Version)
+	}
+	if header.Encoding != cacheEncoding {
+		return nil, fmt.Errorf("unsupported cache encoding %q", header.Encoding)
+	}
+	return &header, nil
+}
+
+// readCacheHeader returns the header of a cache file without touching its payload
+func readCacheHeader(path string) (*cacheHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readHeader(bufio.NewReaderSize(f, maxCacheHeaderSize))
+}
+
+// readCacheFile reads a cache file, verifies its checksum and decodes the payload
+func readCacheFile(path string, payload any) (*cacheHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReaderSize(f, maxCacheHeaderSize)
+	header, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt cache file %s: %w", path, err)
+	}
+	data, err := io.ReadAll(io.LimitReader(zr, header.Size+1))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt cache file %s: %w", path, err)
+	}
+	if sum := fmt.Sprintf("sha256:%x", sha256.Sum256(data)); int64(len(data)) != header.Size || sum != header.Checksum {
+		return nil, fmt.Errorf("corrupt cache file %s: checksum mismatch", path)
+	}
+
+	if err := json.Unmarshal(data, payload); err != nil {
+		return nil, fmt.Errorf("failed to parse cache file %s: %w", path, err)
+	}
+	return header, nil
+}
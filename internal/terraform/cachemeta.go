@@ -0,0 +1,227 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	cacheFilePrefix = "provider_schemas_"
+	cacheFileSuffix = ".cache"
+	cacheMetaSuffix = ".meta.json"
+
+	// legacyCacheFileSuffix is the suffix of the plain JSON entries written before the
+	// envelope format
+	legacyCacheFileSuffix = ".json"
+)
+
+// CacheMetadata describes where a cached schema came from. It is stored in the cache
+// file header so listing the cache does not require reading every schema; entries
+// written before the envelope format keep it in a sidecar file.
+type CacheMetadata struct {
+	SourceDir   string            `json:"source_dir"`
+	DataDir     string            `json:"data_dir,omitempty"`
+	Tool        string            `json:"tool"`
+	Binary      string            `json:"binary,omitempty"`
+	ToolVersion string            `json:"tool_version,omitempty"`
+	Providers   map[string]string `json:"providers,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// CacheEntry is a cached schema file with its metadata, if any was recorded
+type CacheEntry struct {
+	Key      string
+	Path     string
+	Size     int64
+	ModTime  time.Time
+	Metadata *CacheMetadata
+}
+
+// CreatedAt returns the recorded creation time, falling back to the file modification
+// time for entries written before metadata was recorded
+func (e CacheEntry) CreatedAt() time.Time {
+	if e.Metadata != nil && !e.Metadata.CreatedAt.IsZero() {
+		return e.Metadata.CreatedAt
+	}
+	return e.ModTime
+}
+
+func metadataPath(cachePath string) string {
+	return trimCacheFileSuffix(cachePath) + cacheMetaSuffix
+}
+
+// legacyCachePath returns where the plain JSON predecessor of a cache entry was stored
+func legacyCachePath(cachePath string) string {
+	return strings.TrimSuffix(cachePath, cacheFileSuffix) + legacyCacheFileSuffix
+}
+
+// fileExists reports whether a file exists at path
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// isCacheFileName reports whether name is a cache entry, in the current format or a
+// legacy one; sidecar metadata files are not
+func isCacheFileName(name string) bool {
+	return strings.HasSuffix(name, cacheFileSuffix) ||
+		(strings.HasSuffix(name, legacyCacheFileSuffix) && !strings.HasSuffix(name, cacheMetaSuffix))
+}
+
+// trimCacheFileSuffix strips the suffix of a current or legacy cache entry
+func trimCacheFileSuffix(name string) string {
+	if strings.HasSuffix(name, cacheFileSuffix) {
+		return strings.TrimSuffix(name, cacheFileSuffix)
+	}
+	return strings.TrimSuffix(name, legacyCacheFileSuffix)
+}
+
+func newCacheMetadata(id CacheIdentity, schemaWithVersion *SchemaWithVersionInfo) *CacheMetadata {
+	meta := &CacheMetadata{
+		SourceDir:   id.WorkingDir,
+		DataDir:     id.DataDir,
+		Tool:        id.Tool,
+		Binary:      schemaWithVersion.TfInfo.Binary,
+		ToolVersion: id.ToolVersion,
+		Providers:   id.Providers,
+		CreatedAt:   time.Now().UTC(),
+	}
+	// Prefer the versions the tool reports over constraints from required_providers
+	if v := schemaWithVersion.VersionInfo; v != nil && len(v.ProviderSelections) > 0 {
+		meta.Providers = v.ProviderSelections
+	}
+	return meta
+}
+
+func readCacheMetadata(cachePath string) (*CacheMetadata, error) {
+	data, err := os.ReadFile(metadataPath(cachePath))
+	if err != nil {
+		return nil, err
+	}
+	var meta CacheMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse cache metadata: %w", err)
+	}
+	return &meta, nil
+}
+
+// ListCacheEntries returns all cached schemas, newest first
+func ListCacheEntries() ([]CacheEntry, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dirEntries, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var entries []CacheEntry
+	for _, de := range dirEntries {
+		name := de.Name()
+		if de.IsDir() || !strings.HasPrefix(name, cacheFilePrefix) || !isCacheFileName(name) {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(cacheDir, name)
+		entry := CacheEntry{
+			Key:     trimCacheFileSuffix(strings.TrimPrefix(name, cacheFilePrefix)),
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		if header, err := readCacheHeader(path); err == nil {
+			entry.Metadata = header.Metadata
+		} else if meta, err := readCacheMetadata(path); err == nil {
+			entry.Metadata = meta
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt().After(entries[j].CreatedAt())
+	})
+	return entries, nil
+}
+
+// FindCacheEntry returns the entry whose key starts with the given prefix.
+// The prefix must identify exactly one entry.
+func FindCacheEntry(keyPrefix string) (CacheEntry, error) {
+	entries, err := ListCacheEntries()
+	if err != nil {
+		return CacheEntry{}, err
+	}
+
+	var matches []CacheEntry
+	for _, e := range entries {
+		if e.Key == keyPrefix {
+			return e, nil
+		}
+		if keyPrefix != "" && strings.HasPrefix(e.Key, keyPrefix) {
+			matches = append(matches, e)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return CacheEntry{}, fmt.Errorf("no cache entry matches %q", keyPrefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return CacheEntry{}, fmt.Errorf("cache key %q is ambiguous (%d entries match)", keyPrefix, len(matches))
+	}
+}
+
+// RemoveCacheEntry deletes a cached schema and the metadata sidecar of a legacy entry
+func RemoveCacheEntry(entry CacheEntry) error {
+	if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", entry.Path, err)
+	}
+	if err := os.Remove(metadataPath(entry.Path)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove metadata for %s: %w", entry.Key, err)
+	}
+	return nil
+}
+
+// PruneCache removes entries created before the cutoff and returns the removed entries
+func PruneCache(cutoff time.Time) ([]CacheEntry, error) {
+	return removeCacheEntries(func(e CacheEntry) bool {
+		return e.CreatedAt().Before(cutoff)
+	})
+}
+
+// ClearCache removes every cached schema and returns the removed entries
+func ClearCache() ([]CacheEntry, error) {
+	return removeCacheEntries(func(CacheEntry) bool { return true })
+}
+
+func removeCacheEntries(match func(CacheEntry) bool) ([]CacheEntry, error) {
+	entries, err := ListCacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []CacheEntry
+	for _, e := range entries {
+		if !match(e) {
+			continue
+		}
+		if err := RemoveCacheEntry(e); err != nil {
+			return removed, err
+		}
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
@@ -0,0 +1,68 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+)
+
+func TestCacheEntries_MetadataAndPrune(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	workDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, ".terraform.lock.hcl"), []byte("# lock\n"), 0644))
+
+	tfInfo := TerraformInfo{Binary: "terraform", Tool: "terraform"}
+	id, err := NewCacheIdentity(workDir, tfInfo, "1.10.5")
+	require.NoError(t, err)
+
+	err = WriteProviderSchemaToCache(id, &SchemaWithVersionInfo{
+		Schemas: &schema.ProviderSchemas{},
+		VersionInfo: &schema.VersionOutput{
+			Version:            "1.10.5",
+			ProviderSelections: map[string]string{"registry.terraform.io/hashicorp/aws": "6.0.0"},
+		},
+		TfInfo: tfInfo,
+	})
+	require.NoError(t, err)
+
+	// An entry written before metadata was recorded
+	legacy := filepath.Join(home, ".resource-cache", "provider_schemas_legacy.json")
+	require.NoError(t, os.WriteFile(legacy, []byte(`{"schemas":{}}`), 0644))
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(legacy, old, old))
+
+	entries, err := ListCacheEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	current := entries[0]
+	require.NotNil(t, current.Metadata)
+	require.Equal(t, workDir, current.Metadata.SourceDir)
+	require.Equal(t, "terraform", current.Metadata.Tool)
+	require.Equal(t, "1.10.5", current.Metadata.ToolVersion)
+	require.Equal(t, "6.0.0", current.Metadata.Providers["registry.terraform.io/hashicorp/aws"])
+	require.Equal(t, "legacy", entries[1].Key)
+	require.Nil(t, entries[1].Metadata)
+
+	found, err := FindCacheEntry(current.Key[:8])
+	require.NoError(t, err)
+	require.Equal(t, current.Path, found.Path)
+
+	removed, err := PruneCache(time.Now().Add(-30 * 24 * time.Hour))
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	require.Equal(t, "legacy", removed[0].Key)
+
+	removed, err = ClearCache()
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	_, err = os.Stat(metadataPath(current.Path))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,71 @@
+package terraform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+)
+
+// CommandError reports a failed terraform/tofu invocation together with its captured stderr
+type CommandError struct {
+	Binary string
+	Args   []string
+	Dir    string
+	Stderr string
+	Err    error
+}
+
+// Command returns the command line that failed
+func (e *CommandError) Command() string {
+	return strings.Join(append([]string{e.Binary}, e.Args...), " ")
+}
+
+func (e *CommandError) Error() string {
+	msg := fmt.Sprintf("%s failed: %v", e.Command(), e.Err)
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		msg += "\n" + stderr
+	}
+	return msg
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// BinaryNotFound reports whether the command failed because the binary could not be started
+func (e *CommandError) BinaryNotFound() bool {
+	// A bare name missing from PATH yields *exec.Error, an explicit path that does
+	// not exist fails in fork/exec with a *fs.PathError
+	var execErr *exec.Error
+	return errors.As(e.Err, &execErr) || errors.Is(e.Err, fs.ErrNotExist)
+}
+
+// runCommand runs the tool in dir and returns its stdout. Stderr is captured into the
+// returned *CommandError so callers (and the TUI) can show it.
+func runCommand(tfInfo TerraformInfo, dir string, args ...string) ([]byte, error) {
+	env, err := tfInfo.Installation.environ()
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(tfInfo.Binary, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return out, &CommandError{
+			Binary: tfInfo.Binary,
+			Args:   args,
+			Dir:    dir,
+			Stderr: stderr.String(),
+			Err:    err,
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,93 @@
+package terraform
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// InitProcess is a running `init` whose combined output can be consumed line by line
+type InitProcess struct {
+	lines chan string
+	done  chan error
+}
+
+// StartInit starts `init -input=false -no-color` in the working directory. Output lines
+// are delivered on Lines until the process exits; Wait then returns its result.
+// Cancelling ctx kills the process and makes Wait return ctx.Err().
+//
+// With a provider mirror configured, the mirror is checked first so a missing package is
+// reported by name instead of as a failed registry lookup.
+func StartInit(ctx context.Context, workingDir string, tfInfo TerraformInfo) (*InitProcess, error) {
+	if err := CheckMirror(workingDir, tfInfo); err != nil {
+		return nil, err
+	}
+	env, err := tfInfo.Installation.environ()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"init", "-input=false", "-no-color"}
+	cmd := exec.CommandContext(ctx, tfInfo.Binary, args...)
+	cmd.Dir = workingDir
+	cmd.Env = env
+	// Don't hang on output still held open by grandchildren after cancellation
+	cmd.WaitDelay = 2 * time.Second
+
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return nil, &CommandError{Binary: tfInfo.Binary, Args: args, Dir: workingDir, Err: err}
+	}
+
+	p := &InitProcess{
+		lines: make(chan string),
+		done:  make(chan error, 1),
+	}
+
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		waitErr <- err
+	}()
+
+	go func() {
+		var output strings.Builder
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			output.WriteString(line)
+			output.WriteByte('\n')
+			p.lines <- line
+		}
+		// Keep the pipe drained if scanning stopped early (e.g. an overlong line)
+		_, _ = io.Copy(io.Discard, pr)
+		close(p.lines)
+
+		err := <-waitErr
+		switch {
+		case ctx.Err() != nil:
+			err = ctx.Err()
+		case err != nil:
+			err = &CommandError{Binary: tfInfo.Binary, Args: args, Dir: workingDir, Stderr: output.String(), Err: err}
+		}
+		p.done <- err
+	}()
+
+	return p, nil
+}
+
+// Lines returns the output channel, closed when the process has exited
+func (p *InitProcess) Lines() <-chan string {
+	return p.lines
+}
+
+// Wait returns the result of the process once Lines has been drained
+func (p *InitProcess) Wait() error {
+	return <-p.done
+}
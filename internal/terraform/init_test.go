@@ -0,0 +1,50 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "terraform")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755))
+	return path
+}
+
+func TestStartInit_StreamsOutputAndReportsFailure(t *testing.T) {
+	bin := writeScript(t, "echo one\necho two >&2\nexit 3\n")
+
+	proc, err := StartInit(context.Background(), t.TempDir(), TerraformInfo{Binary: bin, Tool: "terraform"})
+	require.NoError(t, err)
+
+	var lines []string
+	for line := range proc.Lines() {
+		lines = append(lines, line)
+	}
+	require.Equal(t, []string{"one", "two"}, lines)
+
+	err = proc.Wait()
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	require.Equal(t, []string{"init", "-input=false", "-no-color"}, cmdErr.Args)
+	require.Contains(t, cmdErr.Stderr, "two")
+}
+
+func TestStartInit_Cancel(t *testing.T) {
+	bin := writeScript(t, "echo started\nexec sleep 30\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	proc, err := StartInit(ctx, t.TempDir(), TerraformInfo{Binary: bin, Tool: "terraform"})
+	require.NoError(t, err)
+
+	require.Equal(t, "started", <-proc.Lines())
+	cancel()
+	for range proc.Lines() {
+	}
+	require.ErrorIs(t, proc.Wait(), context.Canceled)
+}
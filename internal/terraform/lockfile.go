@@ -0,0 +1,107 @@
+package terraform
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// LockedProvider is a provider entry of a dependency lockfile
+type LockedProvider struct {
+	Version     string
+	Constraints string
+	Hashes      []string
+}
+
+// ReadLockfileSelections returns the provider versions selected in the working
+// directory's .terraform.lock.hcl, keyed by full provider source address.
+func ReadLockfileSelections(workingDir string) (map[string]string, error) {
+	locked, err := ReadLockfile(workingDir)
+	if err != nil {
+		return nil, err
+	}
+	selections := make(map[string]string, len(locked))
+	for source, p := range locked {
+		selections[source] = p.Version
+	}
+	return selections, nil
+}
+
+// ReadLockfile returns the provider entries of the working directory's
+// .terraform.lock.hcl, keyed by full provider source address. Entries without a
+// version are skipped.
+func ReadLockfile(workingDir string) (map[string]LockedProvider, error) {
+	path := filepath.Join(workingDir, ".terraform.lock.hcl")
+
+	file, diags := hclparse.NewParser().ParseHCLFile(path)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %s", path, diags.Error())
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unexpected syntax in %s", path)
+	}
+
+	locked := make(map[string]LockedProvider)
+	for _, block := range body.Blocks {
+		if block.Type != "provider" || len(block.Labels) != 1 {
+			continue
+		}
+		source := block.Labels[0]
+		version, ok, err := lockfileString(block, "version")
+		if err != nil {
+			return nil, fmt.Errorf("invalid version for provider %s in %s", source, path)
+		}
+		if !ok {
+			continue
+		}
+		constraints, _, err := lockfileString(block, "constraints")
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraints for provider %s in %s", source, path)
+		}
+		hashes, err := lockfileHashes(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hashes for provider %s in %s", source, path)
+		}
+		locked[source] = LockedProvider{Version: version, Constraints: constraints, Hashes: hashes}
+	}
+	return locked, nil
+}
+
+// lockfileString returns a string attribute of a provider block
+func lockfileString(block *hclsyntax.Block, name string) (string, bool, error) {
+	attr, ok := block.Body.Attributes[name]
+	if !ok {
+		return "", false, nil
+	}
+	val, diags := attr.Expr.Value(&hcl.EvalContext{})
+	if diags.HasErrors() || val.Type() != cty.String || val.IsNull() {
+		return "", false, fmt.Errorf("%s is not a string", name)
+	}
+	return val.AsString(), true, nil
+}
+
+// lockfileHashes returns the package hashes of a provider block
+func lockfileHashes(block *hclsyntax.Block) ([]string, error) {
+	attr, ok := block.Body.Attributes["hashes"]
+	if !ok {
+		return nil, nil
+	}
+	val, diags := attr.Expr.Value(&hcl.EvalContext{})
+	if diags.HasErrors() || val.IsNull() || !(val.Type().IsTupleType() || val.Type().IsListType()) {
+		return nil, fmt.Errorf("hashes is not a list")
+	}
+	var hashes []string
+	for it := val.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if v.Type() != cty.String || v.IsNull() {
+			return nil, fmt.Errorf("hashes must be strings")
+		}
+		hashes = append(hashes, v.AsString())
+	}
+	return hashes, nil
+}
@@ -0,0 +1,247 @@
+package terraform
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+)
+
+// ProviderInstallation overrides where the tool installs providers from. The zero value
+// leaves the user's own CLI configuration in charge.
+type ProviderInstallation struct {
+	// MirrorDir is a filesystem mirror (as written by `providers mirror`) that providers
+	// are installed from exclusively, so init never reaches out to a registry
+	MirrorDir string
+	// PluginCacheDir is the plugin cache directory shared between workspaces
+	PluginCacheDir string
+}
+
+// IsZero reports whether no override is configured
+func (inst ProviderInstallation) IsZero() bool {
+	return inst == ProviderInstallation{}
+}
+
+// cliConfig renders a CLI configuration file for the overrides
+func (inst ProviderInstallation) cliConfig() string {
+	var b strings.Builder
+	if inst.PluginCacheDir != "" {
+		fmt.Fprintf(&b, "plugin_cache_dir = %q\n", inst.PluginCacheDir)
+	}
+	if inst.MirrorDir != "" {
+		fmt.Fprintf(&b, "provider_installation {\n  filesystem_mirror {\n    path    = %q\n    include = [\"*/*/*\"]\n  }\n}\n", inst.MirrorDir)
+	}
+	return b.String()
+}
+
+// environ returns the environment for running the tool with the overrides, or nil to
+// inherit ours unchanged. The CLI configuration is written once per distinct override
+// into the cache directory, so nothing needs cleaning up.
+func (inst ProviderInstallation) environ() ([]string, error) {
+	if inst.IsZero() {
+		return nil, nil
+	}
+
+	config := inst.cliConfig()
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(cacheDir, "cliconfig", fmt.Sprintf("%x.tfrc", sha256.Sum256([]byte(config))))
+	if existing, err := os.ReadFile(path); err != nil || string(existing) != config {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create CLI config directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write CLI config: %w", err)
+		}
+	}
+
+	env := append(os.Environ(), "TF_CLI_CONFIG_FILE="+path)
+	if inst.PluginCacheDir != "" {
+		// The environment variable would otherwise win over the configuration file
+		env = append(env, "TF_PLUGIN_CACHE_DIR="+inst.PluginCacheDir)
+	}
+	return env, nil
+}
+
+// MissingMirrorPackageError reports a required provider that the mirror has no package
+// for on this platform
+type MissingMirrorPackageError struct {
+	Source      string // full source address
+	Constraints string // locked version or version constraints, empty for any version
+	Platform    string // <os>_<arch>
+	MirrorDir   string
+}
+
+func (e *MissingMirrorPackageError) Error() string {
+	version := e.Constraints
+	if version == "" {
+		version = "any version"
+	}
+	typeName := e.Source[strings.LastIndex(e.Source, "/")+1:]
+	return fmt.Sprintf("provider package %s (%s, %s) is missing from mirror %s; expected %s/<version>/%s/ or %s/terraform-provider-%s_<version>_%s.zip",
+		e.Source, version, e.Platform, e.MirrorDir,
+		e.Source, e.Platform, e.Source, typeName, e.Platform)
+}
+
+// CheckMirror verifies that the mirror holds a package for every provider the working
+// directory and its modules need on this platform: the locked versions when there is a lockfile,
+// otherwise a version matching the required_providers constraints. Init against an
+// incomplete mirror only reports that a provider was not found in any search location;
+// this names the package to add instead.
+func CheckMirror(workingDir string, tfInfo TerraformInfo) error {
+	mirror := tfInfo.Installation.MirrorDir
+	if mirror == "" {
+		return nil
+	}
+
+	required, err := requiredProviderVersions(workingDir, tfInfo)
+	if err != nil {
+		return err
+	}
+
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	sources := make([]string, 0, len(required))
+	for source := range required {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		constraints := required[source]
+		if !mirrorHasPackage(mirror, source, constraints, platform) {
+			return &MissingMirrorPackageError{Source: source, Constraints: constraints, Platform: platform, MirrorDir: mirror}
+		}
+	}
+	return nil
+}
+
+// requiredProviderVersions maps the providers of a working directory and the modules it
+// calls to their locked version, or to their version constraints when they are not
+// locked. Modules are read from their local source or from where init installed them;
+// remote modules init has not installed yet are not known here.
+func requiredProviderVersions(workingDir string, tfInfo TerraformInfo) (map[string]string, error) {
+	dirs, err := moduleDirs(workingDir)
+	if err != nil {
+		return nil, err
+	}
+	selections, _ := ReadLockfileSelections(workingDir)
+
+	constraints := make(map[string][]string)
+	for _, dir := range dirs {
+		module, diags := tfconfig.LoadModule(dir)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("failed to load terraform module %s: %v", dir, diags)
+		}
+		for name, req := range module.RequiredProviders {
+			source := req.Source
+			if source == "" {
+				source = "hashicorp/" + name
+			}
+			if strings.HasPrefix(source, "terraform.io/builtin/") {
+				continue
+			}
+			address, err := ProviderSourceAddress(source, tfInfo)
+			if err != nil {
+				return nil, err
+			}
+			constraints[address] = append(constraints[address], req.VersionConstraints...)
+		}
+	}
+
+	required := make(map[string]string, len(constraints))
+	for address, versions := range constraints {
+		if version, ok := selections[address]; ok {
+			required[address] = version
+		} else {
+			required[address] = strings.Join(versions, ",")
+		}
+	}
+	return required, nil
+}
+
+// moduleDirs returns the directory of the root module followed by those of the modules
+// it calls, directly or through other modules: local sources relative to their caller,
+// and the modules recorded in the manifest init writes into the data directory
+func moduleDirs(workingDir string) ([]string, error) {
+	absDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, err
+	}
+	dirs := []string{absDir}
+	seen := map[string]bool{absDir: true}
+	add := func(dir string) {
+		if dir = filepath.Clean(dir); !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	installed, err := ReadModuleManifest(absDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, mod := range installed {
+		add(filepath.Join(absDir, filepath.FromSlash(mod.Dir)))
+	}
+
+	// Local calls are followed even before init, when there is no manifest yet
+	for i := 0; i < len(dirs); i++ {
+		module, _ := tfconfig.LoadModule(dirs[i])
+		for _, call := range module.ModuleCalls {
+			if IsLocalModuleSource(call.Source) {
+				add(filepath.Join(dirs[i], filepath.FromSlash(call.Source)))
+			}
+		}
+	}
+	return dirs, nil
+}
+
+// mirrorHasPackage reports whether a filesystem mirror holds a package of source matching
+// constraints for platform, in either the unpacked or the packed layout
+func mirrorHasPackage(mirror, source, constraints, platform string) bool {
+	var constraint goversion.Constraints
+	if constraints != "" {
+		var err error
+		if constraint, err = goversion.NewConstraint(constraints); err != nil {
+			return false
+		}
+	}
+	matches := func(version string) bool {
+		if constraint == nil {
+			return true
+		}
+		v, err := goversion.NewVersion(version)
+		return err == nil && constraint.Check(v)
+	}
+
+	dir := filepath.Join(mirror, filepath.FromSlash(source))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	typeName := filepath.Base(dir)
+	packedPrefix := "terraform-provider-" + typeName + "_"
+	packedSuffix := "_" + platform + ".zip"
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+			if _, err := os.Stat(filepath.Join(dir, name, platform)); err == nil && matches(name) {
+				return true
+			}
+		case strings.HasPrefix(name, packedPrefix) && strings.HasSuffix(name, packedSuffix):
+			if matches(strings.TrimSuffix(strings.TrimPrefix(name, packedPrefix), packedSuffix)) {
+				return true
+			}
+		}
+	}
+	return false
+}
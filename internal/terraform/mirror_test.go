@@ -0,0 +1,109 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const mirrorTestConfig = `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 6.0"
+    }
+    random = {}
+  }
+}
+`
+
+func TestCheckMirror(t *testing.T) {
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(mirrorTestConfig), 0644))
+
+	mirror := t.TempDir()
+	tfInfo := TerraformInfo{Tool: "terraform", Registry: "registry.terraform.io", Installation: ProviderInstallation{MirrorDir: mirror}}
+	aws := filepath.Join(mirror, "registry.terraform.io", "hashicorp", "aws")
+	require.NoError(t, os.MkdirAll(filepath.Join(aws, "5.9.0", platform), 0755))
+	random := filepath.Join(mirror, "registry.terraform.io", "hashicorp", "random")
+	require.NoError(t, os.MkdirAll(random, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(random, "terraform-provider-random_3.6.0_"+platform+".zip"), nil, 0644))
+
+	// Only a version outside the constraints is mirrored
+	var missing *MissingMirrorPackageError
+	require.ErrorAs(t, CheckMirror(dir, tfInfo), &missing)
+	require.Equal(t, "registry.terraform.io/hashicorp/aws", missing.Source)
+	require.Equal(t, "~> 6.0", missing.Constraints)
+	require.Contains(t, missing.Error(), "terraform-provider-aws_<version>_"+platform+".zip")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(aws, "6.1.0", platform), 0755))
+	require.NoError(t, CheckMirror(dir, tfInfo))
+
+	// A lockfile pins the exact version
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), []byte(testLockfile), 0644))
+	require.ErrorAs(t, CheckMirror(dir, tfInfo), &missing)
+	require.Equal(t, "6.0.0", missing.Constraints)
+
+	// Without a mirror there is nothing to check
+	require.NoError(t, CheckMirror(dir, TerraformInfo{Tool: "terraform"}))
+}
+
+func TestCheckMirror_ModuleProviders(t *testing.T) {
+	t.Setenv("TF_DATA_DIR", "")
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	dir := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write("main.tf", "module \"app\" {\n  source = \"./modules/app\"\n}\n")
+	write("modules/app/main.tf", "terraform {\n  required_providers {\n    random = { version = \">= 3.0\" }\n  }\n}\n")
+
+	mirror := t.TempDir()
+	tfInfo := TerraformInfo{Tool: "terraform", Registry: "registry.terraform.io", Installation: ProviderInstallation{MirrorDir: mirror}}
+
+	// Providers required by a local module call are checked
+	var missing *MissingMirrorPackageError
+	require.ErrorAs(t, CheckMirror(dir, tfInfo), &missing)
+	require.Equal(t, "registry.terraform.io/hashicorp/random", missing.Source)
+	require.Equal(t, ">= 3.0", missing.Constraints)
+
+	// And so are those of modules init installed
+	require.NoError(t, os.MkdirAll(filepath.Join(mirror, "registry.terraform.io", "hashicorp", "random", "3.6.0", platform), 0755))
+	require.NoError(t, CheckMirror(dir, tfInfo))
+	write(".terraform/modules/modules.json", `{"Modules":[{"Key":"","Dir":"."},{"Key":"app","Dir":"modules/app"},{"Key":"db","Dir":".terraform/modules/db"},{"Key":"gone","Dir":".terraform/modules/gone"}]}`)
+	write(".terraform/modules/db/main.tf", "terraform {\n  required_providers {\n    time = { source = \"hashicorp/time\" }\n  }\n}\n")
+	require.ErrorAs(t, CheckMirror(dir, tfInfo), &missing)
+	require.Equal(t, "registry.terraform.io/hashicorp/time", missing.Source)
+}
+
+func TestStartInit_UsesProviderInstallation(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TF_PLUGIN_CACHE_DIR", "/from/env")
+	bin := writeScript(t, "cat \"$TF_CLI_CONFIG_FILE\"\necho \"cache=$TF_PLUGIN_CACHE_DIR\"\n")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte("# no providers\n"), 0644))
+	inst := ProviderInstallation{MirrorDir: "/mirror", PluginCacheDir: "/plugin-cache"}
+
+	proc, err := StartInit(context.Background(), dir, TerraformInfo{Binary: bin, Tool: "terraform", Installation: inst})
+	require.NoError(t, err)
+	var lines []string
+	for line := range proc.Lines() {
+		lines = append(lines, line)
+	}
+	require.NoError(t, proc.Wait())
+
+	output := strings.Join(lines, "\n")
+	require.Contains(t, output, `plugin_cache_dir = "/plugin-cache"`)
+	require.Contains(t, output, `path    = "/mirror"`)
+	require.Contains(t, output, "cache=/plugin-cache")
+}
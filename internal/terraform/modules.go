@@ -0,0 +1,52 @@
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InstalledModule is a module init installed for a working directory, as recorded in
+// the modules.json manifest it writes into <data dir>/modules
+type InstalledModule struct {
+	// Key is the module's address below the root module, e.g. vpc.subnets; the root
+	// module itself has an empty key
+	Key     string `json:"Key"`
+	Source  string `json:"Source"`
+	Version string `json:"Version"`
+	Dir     string `json:"Dir"` // relative to the working directory
+}
+
+// ReadModuleManifest returns the modules init installed for a working directory; one
+// without installed modules has none. Entries whose directory was removed since init
+// are left out.
+func ReadModuleManifest(workingDir string) ([]InstalledModule, error) {
+	data, err := os.ReadFile(filepath.Join(DataDir(workingDir), "modules", "modules.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module manifest: %w", err)
+	}
+	var manifest struct {
+		Modules []InstalledModule `json:"Modules"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse module manifest: %w", err)
+	}
+	var installed []InstalledModule
+	for _, mod := range manifest.Modules {
+		if _, err := os.Stat(filepath.Join(workingDir, filepath.FromSlash(mod.Dir))); err == nil {
+			installed = append(installed, mod)
+		}
+	}
+	return installed, nil
+}
+
+// IsLocalModuleSource reports whether a module source is a path relative to the caller
+func IsLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
@@ -0,0 +1,30 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadModuleManifest(t *testing.T) {
+	t.Setenv("TF_DATA_DIR", "")
+	dir := t.TempDir()
+
+	installed, err := ReadModuleManifest(dir)
+	require.NoError(t, err)
+	require.Empty(t, installed)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".terraform", "modules", "vpc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".terraform", "modules", "modules.json"),
+		[]byte(`{"Modules":[{"Key":"","Dir":"."},{"Key":"vpc","Source":"terraform-aws-modules/vpc/aws","Version":"5.0.0","Dir":".terraform/modules/vpc"},{"Key":"gone","Dir":".terraform/modules/gone"}]}`), 0644))
+
+	// Modules removed since init are left out
+	installed, err = ReadModuleManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, []InstalledModule{
+		{Dir: "."},
+		{Key: "vpc", Source: "terraform-aws-modules/vpc/aws", Version: "5.0.0", Dir: ".terraform/modules/vpc"},
+	}, installed)
+}
@@ -0,0 +1,103 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/terraconstructs/provider-explorer/internal/plugin"
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+)
+
+// pluginDirs returns the directories searched for provider plugins: the workspace's
+// installed providers, then the given extra directories, then the configured plugin
+// cache and unpacked mirror, then TF_PLUGIN_CACHE_DIR
+func pluginDirs(workingDir string, extra []string, inst ProviderInstallation) ([]string, error) {
+	absDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, err
+	}
+	dirs := []string{filepath.Join(DataDir(absDir), "providers")}
+	dirs = append(dirs, extra...)
+	for _, dir := range []string{inst.PluginCacheDir, inst.MirrorDir} {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	if cacheDir := os.Getenv("TF_PLUGIN_CACHE_DIR"); cacheDir != "" {
+		dirs = append(dirs, cacheDir)
+	}
+	return dirs, nil
+}
+
+// FetchProviderSchemasFromPlugins reads the provider schemas of a working directory
+// straight from the provider plugin binaries, without running `providers schema -json`.
+// Only the binaries are needed: those installed by init, or the ones in extraDirs or
+// TF_PLUGIN_CACHE_DIR. The lockfile selects the versions when there is one, otherwise
+// the newest installed version of every provider is used.
+//
+// The builtin terraform provider lives inside the tool and is therefore not included.
+func FetchProviderSchemasFromPlugins(ctx context.Context, workingDir string, tfInfo TerraformInfo, extraDirs []string) (*SchemaWithVersionInfo, error) {
+	dirs, err := pluginDirs(workingDir, extraDirs, tfInfo.Installation)
+	if err != nil {
+		return nil, err
+	}
+
+	var selections map[string]string
+	if _, err := os.Stat(filepath.Join(workingDir, ".terraform.lock.hcl")); err == nil {
+		if selections, err = ReadLockfileSelections(workingDir); err != nil {
+			return nil, err
+		}
+	}
+
+	providers := plugin.FindProviders(dirs, selections)
+	if selections != nil {
+		var missing []string
+		for source, version := range selections {
+			if !containsProvider(providers, source) {
+				missing = append(missing, source+" "+version)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return nil, fmt.Errorf("no installed plugin for %s in %s; run '%s init' or point --plugin-dir at a plugin cache",
+				strings.Join(missing, ", "), strings.Join(dirs, ", "), tfInfo.Tool)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no provider plugins found in %s", strings.Join(dirs, ", "))
+	}
+
+	schemas, err := plugin.LoadProviderSchemas(ctx, providers)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tool is optional here; its version only drives feature gating
+	versionInfo, err := DetectVersion(tfInfo, workingDir)
+	if err != nil {
+		versionInfo = &schema.VersionOutput{}
+	}
+	versionInfo.ProviderSelections = make(map[string]string, len(providers))
+	for _, p := range providers {
+		versionInfo.ProviderSelections[p.Source] = p.Version
+	}
+
+	return &SchemaWithVersionInfo{
+		Schemas:     schemas,
+		VersionInfo: versionInfo,
+		TfInfo:      tfInfo,
+	}, nil
+}
+
+func containsProvider(providers []plugin.Provider, source string) bool {
+	for _, p := range providers {
+		if p.Source == source {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchProviderSchemasFromPlugins(t *testing.T) {
+	tfInfo := TerraformInfo{Binary: writeScript(t, `echo '{"terraform_version":"1.10.5"}'`), Tool: "terraform"}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), []byte(testLockfile), 0644))
+
+	// Nothing installed yet
+	_, err := FetchProviderSchemasFromPlugins(context.Background(), dir, tfInfo, nil)
+	require.ErrorContains(t, err, "no installed plugin for registry.terraform.io/hashicorp/aws 6.0.0")
+
+	// The selected version in a plugin cache directory is enough
+	cacheDir := t.TempDir()
+	plugin := filepath.Join(cacheDir, "registry.terraform.io", "hashicorp", "aws", "6.0.0", runtime.GOOS+"_"+runtime.GOARCH, "terraform-provider-aws_v6.0.0")
+	out, err := exec.Command("go", "build", "-o", plugin, "../plugin/testdata/testprovider").CombinedOutput()
+	require.NoError(t, err, string(out))
+	t.Setenv("TF_PLUGIN_CACHE_DIR", cacheDir)
+
+	result, err := FetchProviderSchemasFromPlugins(context.Background(), dir, tfInfo, nil)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", result.Schemas.FormatVersion)
+	require.Contains(t, result.Schemas.Schemas["registry.terraform.io/hashicorp/aws"].ResourceSchemas, "test_thing")
+	require.Equal(t, "1.10.5", result.VersionInfo.Version)
+	require.Equal(t, map[string]string{"registry.terraform.io/hashicorp/aws": "6.0.0"}, result.VersionInfo.ProviderSelections)
+}
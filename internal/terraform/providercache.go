@@ -0,0 +1,341 @@
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+)
+
+// builtinProviderSource is the provider shipped inside terraform and tofu. It has no
+// lockfile entry, so it is cached per tool version instead.
+const builtinProviderSource = "terraform.io/builtin/terraform"
+
+// legacyProviderCacheFile is the plain JSON form provider entries had before the
+// envelope format
+type legacyProviderCacheFile struct {
+	Source    string                 `json:"source"`
+	Version   string                 `json:"version"`
+	CreatedAt time.Time              `json:"created_at"`
+	Schema    *schema.ProviderSchema `json:"schema"`
+}
+
+// ProviderCacheEntry is a provider schema cached under its source address and version
+type ProviderCacheEntry struct {
+	Source  string
+	Version string
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Key returns the entry's cache key in source@version form
+func (e ProviderCacheEntry) Key() string {
+	return e.Source + "@" + e.Version
+}
+
+// ProviderCacheHeader is what the header of a provider entry records about it
+type ProviderCacheHeader struct {
+	Provider  string // source@version of the schema the entry holds
+	Format    string // format name and version, e.g. provider-explorer-cache/1
+	Encoding  string
+	Checksum  string // sha256 of the uncompressed payload
+	Size      int64  // length of the uncompressed payload
+	CreatedAt time.Time
+}
+
+// ReadHeader reads the header of the entry; legacy entries written before the envelope
+// format have none
+func (e ProviderCacheEntry) ReadHeader() (*ProviderCacheHeader, error) {
+	header, err := readCacheHeader(e.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &ProviderCacheHeader{
+		Provider:  header.Provider,
+		Format:    fmt.Sprintf("%s/%d", header.Format, header.Version),
+		Encoding:  header.Encoding,
+		Checksum:  header.Checksum,
+		Size:      header.Size,
+		CreatedAt: header.CreatedAt,
+	}, nil
+}
+
+func getProviderCacheDir() (string, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "providers"), nil
+}
+
+// providerCachePath returns <cache>/providers/<host>/<namespace>/<type>/<version>.cache
+func providerCachePath(source, version string) (string, error) {
+	dir, err := getProviderCacheDir()
+	if err != nil {
+		return "", err
+	}
+	parts := append(strings.Split(source, "/"), version+cacheFileSuffix)
+	for _, part := range parts {
+		if part == "" || part == "." || part == ".." || strings.ContainsAny(part, `\:`) {
+			return "", fmt.Errorf("invalid provider cache key %s@%s", source, version)
+		}
+	}
+	return filepath.Join(append([]string{dir}, parts...)...), nil
+}
+
+// providerCacheKeys returns the source → version keys identifying the providers of a
+// workspace: the lockfile selections plus the builtin provider keyed by tool version.
+func providerCacheKeys(selections map[string]string, tfInfo TerraformInfo, versionInfo *schema.VersionOutput) map[string]string {
+	keys := make(map[string]string, len(selections)+1)
+	for source, version := range selections {
+		keys[source] = version
+	}
+	if versionInfo != nil && versionInfo.Version != "" && tfInfo.Tool != "" {
+		keys[builtinProviderSource] = tfInfo.Tool + "-" + versionInfo.Version
+	}
+	return keys
+}
+
+// splitProviderKey splits a source@version key
+func splitProviderKey(key string) (source, version string) {
+	if i := strings.LastIndex(key, "@"); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return key, ""
+}
+
+func readProviderFromCache(source, version string) (*schema.ProviderSchema, error) {
+	path, err := providerCachePath(source, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var ps schema.ProviderSchema
+	header, err := readCacheFile(path, &ps)
+	if errors.Is(err, errLegacyCacheFormat) {
+		return migrateProviderCache(source, version, path)
+	}
+	if legacyPath := legacyCachePath(path); os.IsNotExist(err) && fileExists(legacyPath) {
+		return migrateProviderCache(source, version, legacyPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key := source + "@" + version; header.Provider != key {
+		return nil, fmt.Errorf("cache file %s holds %s, not %s", path, header.Provider, key)
+	}
+	return &ps, nil
+}
+
+// migrateProviderCache rewrites the legacy provider entry at path in the current format,
+// or discards it when it does not hold the expected provider
+func migrateProviderCache(source, version, path string) (*schema.ProviderSchema, error) {
+	var legacy legacyProviderCacheFile
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &legacy)
+	}
+	if err != nil || legacy.Source != source || legacy.Version != version || legacy.Schema == nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("discarded legacy cache file %s", path)
+	}
+
+	if err := writeProviderToCache(source, version, legacy.Schema); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to migrate cache file: %v\n", err)
+	} else if current, err := providerCachePath(source, version); err == nil && current != path {
+		os.Remove(path)
+	}
+	return legacy.Schema, nil
+}
+
+func writeProviderToCache(source, version string, ps *schema.ProviderSchema) error {
+	path, err := providerCachePath(source, version)
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(path, cacheHeader{Provider: source + "@" + version}, ps)
+}
+
+// providersCached reports, from cache headers alone, whether every provider in keys is cached
+func providersCached(keys map[string]string) bool {
+	for source, version := range keys {
+		path, err := providerCachePath(source, version)
+		if err != nil {
+			return false
+		}
+		header, err := readCacheHeader(path)
+		if err != nil || header.Provider != source+"@"+version {
+			return false
+		}
+	}
+	return len(keys) > 0
+}
+
+// readProvidersFromCache assembles a schema document from per-provider entries. It only
+// succeeds when every locked provider in keys is cached; the builtin provider is
+// optional because not every tool version reports it.
+func readProvidersFromCache(keys map[string]string) (*schema.ProviderSchemas, bool) {
+	if len(keys) == 0 {
+		return nil, false
+	}
+	schemas := &schema.ProviderSchemas{
+		FormatVersion: "1.0",
+		Schemas:       make(map[string]*schema.ProviderSchema, len(keys)),
+	}
+	for source, version := range keys {
+		ps, err := readProviderFromCache(source, version)
+		if err != nil {
+			if source == builtinProviderSource && os.IsNotExist(err) {
+				continue
+			}
+			return nil, false
+		}
+		schemas.Schemas[source] = ps
+	}
+	return schemas, true
+}
+
+// writeProvidersToCache stores every provider of a schema document that has a cache key
+func writeProvidersToCache(keys map[string]string, schemas *schema.ProviderSchemas) error {
+	for source, ps := range schemas.Schemas {
+		version, ok := keys[source]
+		if !ok {
+			continue
+		}
+		if err := writeProviderToCache(source, version, ps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListProviderCacheEntries returns all per-provider cache entries sorted by key
+func ListProviderCacheEntries() ([]ProviderCacheEntry, error) {
+	dir, err := getProviderCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ProviderCacheEntry
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !isCacheFileName(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		entries = append(entries, ProviderCacheEntry{
+			Source:  filepath.ToSlash(filepath.Dir(rel)),
+			Version: trimCacheFileSuffix(d.Name()),
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider cache: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key() < entries[j].Key()
+	})
+	return entries, nil
+}
+
+// FindProviderCacheEntry returns the entry for a source@version key. The source may be
+// given without its registry host, e.g. hashicorp/aws@6.0.0.
+func FindProviderCacheEntry(key string) (ProviderCacheEntry, error) {
+	source, version, ok := strings.Cut(key, "@")
+	if !ok || source == "" || version == "" {
+		return ProviderCacheEntry{}, fmt.Errorf("invalid provider cache key %q (expected source@version)", key)
+	}
+
+	entries, err := ListProviderCacheEntries()
+	if err != nil {
+		return ProviderCacheEntry{}, err
+	}
+	var matches []ProviderCacheEntry
+	for _, e := range entries {
+		if e.Version == version && (e.Source == source || strings.HasSuffix(e.Source, "/"+source)) {
+			matches = append(matches, e)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return ProviderCacheEntry{}, fmt.Errorf("no cached provider matches %q", key)
+	case 1:
+		return matches[0], nil
+	default:
+		return ProviderCacheEntry{}, fmt.Errorf("provider cache key %q is ambiguous (%d entries match)", key, len(matches))
+	}
+}
+
+// RemoveProviderCacheEntry deletes a cached provider schema and any directories left empty
+func RemoveProviderCacheEntry(entry ProviderCacheEntry) error {
+	if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", entry.Path, err)
+	}
+
+	root, err := getProviderCacheDir()
+	if err != nil {
+		return nil
+	}
+	for dir := filepath.Dir(entry.Path); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		// Fails, and stops the walk, as soon as a directory is not empty
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// PruneProviderCache removes provider entries written before the cutoff
+func PruneProviderCache(cutoff time.Time) ([]ProviderCacheEntry, error) {
+	return removeProviderCacheEntries(func(e ProviderCacheEntry) bool {
+		return e.ModTime.Before(cutoff)
+	})
+}
+
+// ClearProviderCache removes every cached provider schema
+func ClearProviderCache() ([]ProviderCacheEntry, error) {
+	return removeProviderCacheEntries(func(ProviderCacheEntry) bool { return true })
+}
+
+func removeProviderCacheEntries(match func(ProviderCacheEntry) bool) ([]ProviderCacheEntry, error) {
+	entries, err := ListProviderCacheEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []ProviderCacheEntry
+	for _, e := range entries {
+		if !match(e) {
+			continue
+		}
+		if err := RemoveProviderCacheEntry(e); err != nil {
+			return removed, err
+		}
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
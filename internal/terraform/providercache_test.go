@@ -0,0 +1,112 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+)
+
+const testLockfile = `# This file is maintained automatically by "terraform init".
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "6.0.0"
+  constraints = "~> 6.0"
+  hashes = [
+    "h1:abc=",
+  ]
+}
+`
+
+func TestReadLockfileSelections(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), []byte(testLockfile), 0644))
+
+	selections, err := ReadLockfileSelections(dir)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"registry.terraform.io/hashicorp/aws": "6.0.0"}, selections)
+
+	_, err = ReadLockfileSelections(t.TempDir())
+	require.Error(t, err)
+}
+
+func TestFetchAllProviderSchemas_SharesProviderCacheAcrossWorkspaces(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	fetching := writeScript(t, `case "$1" in
+version) echo '{"terraform_version":"1.10.5"}' ;;
+providers) echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/aws":{"resource_schemas":{"aws_s3_bucket":{"version":0,"block":{}}}}}}' ;;
+esac
+`)
+	first := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(first, ".terraform.lock.hcl"), []byte(testLockfile), 0644))
+
+	result, err := FetchAllProviderSchemas(first, TerraformInfo{Binary: fetching, Tool: "terraform"})
+	require.NoError(t, err)
+	require.Contains(t, result.Schemas.Schemas, "registry.terraform.io/hashicorp/aws")
+
+	entries, err := ListProviderCacheEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "registry.terraform.io/hashicorp/aws@6.0.0", entries[0].Key())
+
+	// A second workspace locking the same version must not ask the tool for schemas
+	failing := writeScript(t, `case "$1" in
+version) echo '{"terraform_version":"1.10.5"}' ;;
+*) echo "providers schema must not run" >&2; exit 1 ;;
+esac
+`)
+	second := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(second, ".terraform.lock.hcl"), []byte(testLockfile), 0644))
+
+	result, err = FetchAllProviderSchemas(second, TerraformInfo{Binary: failing, Tool: "terraform"})
+	require.NoError(t, err)
+	require.Contains(t, result.Schemas.Schemas["registry.terraform.io/hashicorp/aws"].ResourceSchemas, "aws_s3_bucket")
+	require.True(t, HasValidProviderCache(second, TerraformInfo{Binary: failing, Tool: "terraform"}))
+
+	entry, err := FindProviderCacheEntry("hashicorp/aws@6.0.0")
+	require.NoError(t, err)
+	require.NoError(t, RemoveProviderCacheEntry(entry))
+
+	entries, err = ListProviderCacheEntries()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.False(t, HasValidProviderCache(second, TerraformInfo{Binary: failing, Tool: "terraform"}))
+}
+
+func TestReadProviderFromCache_MigratesLegacyEntry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	source, version := "registry.terraform.io/hashicorp/aws", "6.0.0"
+	path, err := providerCachePath(source, version)
+	require.NoError(t, err)
+	require.Equal(t, version+cacheFileSuffix, filepath.Base(path))
+
+	// A plain JSON entry under the old suffix is listed, and rewritten when read
+	legacy, err := json.Marshal(legacyProviderCacheFile{Source: source, Version: version, Schema: &schema.ProviderSchema{}})
+	require.NoError(t, err)
+	legacyPath := legacyCachePath(path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(legacyPath), 0755))
+	require.NoError(t, os.WriteFile(legacyPath, legacy, 0644))
+
+	entries, err := ListProviderCacheEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, source+"@"+version, entries[0].Key())
+
+	_, err = readProviderFromCache(source, version)
+	require.NoError(t, err)
+	require.False(t, fileExists(legacyPath))
+	header, err := readCacheHeader(path)
+	require.NoError(t, err)
+	require.Equal(t, source+"@"+version, header.Provider)
+
+	entries, err = ListProviderCacheEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, path, entries[0].Path)
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/terraconstructs/provider-explorer/internal/schema"
 )
@@ -14,24 +13,63 @@ type SchemaWithVersionInfo struct {
 	Schemas     *schema.ProviderSchemas `json:"schemas"`
 	VersionInfo *schema.VersionOutput   `json:"version_info,omitempty"`
 	TfInfo      TerraformInfo           `json:"terraform_info"`
+
+	// FromCache reports that the schemas were read from the cache rather than the tool
+	FromCache bool `json:"-"`
+}
+
+// FetchAllProviderSchemas returns the provider schemas for a working directory, using the
+// cache when possible and otherwise running `providers schema -json` with the given tool.
+//
+// Workspaces with a lockfile use the per-provider cache keyed by source@version, so a
+// workspace whose selected providers were all fetched before (by any workspace) opens
+// without running `providers schema` at all.
+func FetchAllProviderSchemas(workingDir string, tfInfo TerraformInfo) (*SchemaWithVersionInfo, error) {
+	return fetchProviderSchemas(workingDir, tfInfo, true)
+}
+
+// RefreshProviderSchemas always runs `providers schema -json` and replaces the cached
+// schemas with the result. It is used to revalidate a session opened from the cache.
+func RefreshProviderSchemas(workingDir string, tfInfo TerraformInfo) (*SchemaWithVersionInfo, error) {
+	return fetchProviderSchemas(workingDir, tfInfo, false)
 }
 
-func FetchAllProviderSchemas(workingDir string) (*SchemaWithVersionInfo, error) {
-	tfInfo := FindTerraformBinary()
+func fetchProviderSchemas(workingDir string, tfInfo TerraformInfo, useCache bool) (*SchemaWithVersionInfo, error) {
+	// The tool version is part of the cache identity, keys the builtin provider and
+	// drives feature gating
+	versionInfo, versionErr := DetectVersion(tfInfo, workingDir)
+	var toolVersion string
+	if versionInfo != nil {
+		toolVersion = versionInfo.Version
+	}
 
-	if cachedSchema, err := ReadProviderSchemaFromCache(workingDir); err == nil {
-		// Always update TfInfo with current binary detection to ensure correct tool priority
-		cachedSchema.TfInfo = tfInfo
-		return cachedSchema, nil
+	id, idErr := NewCacheIdentity(workingDir, tfInfo, toolVersion)
+	if useCache && idErr == nil {
+		if cachedSchema, err := ReadProviderSchemaFromCache(id); err == nil {
+			// Always report the selected tool rather than the one that populated the cache
+			cachedSchema.TfInfo = tfInfo
+			cachedSchema.FromCache = true
+			return cachedSchema, nil
+		}
+	}
+
+	selections, _ := ReadLockfileSelections(workingDir)
+	keys := providerCacheKeys(selections, tfInfo, versionInfo)
+	if useCache && len(selections) > 0 {
+		if schemas, ok := readProvidersFromCache(keys); ok {
+			return &SchemaWithVersionInfo{
+				Schemas:     schemas,
+				VersionInfo: versionInfo,
+				TfInfo:      tfInfo,
+				FromCache:   true,
+			}, nil
+		}
 	}
 
 	// Fetch provider schemas from the tool
-	schemaCmd := exec.Command(tfInfo.Binary, "providers", "schema", "-json")
-	schemaCmd.Dir = workingDir
-	schemaCmd.Stderr = os.Stderr
-	schemaOutput, err := schemaCmd.Output()
+	schemaOutput, err := runCommand(tfInfo, workingDir, "providers", "schema", "-json")
 	if err != nil {
-		return nil, fmt.Errorf("terraform providers schema failed: %w", err)
+		return nil, err
 	}
 
 	var providerSchemas schema.ProviderSchemas
@@ -40,18 +78,8 @@ func FetchAllProviderSchemas(workingDir string) (*SchemaWithVersionInfo, error)
 		return nil, fmt.Errorf("failed to parse schema output: %w", err)
 	}
 
-	// Get version information
-	var versionInfo *schema.VersionOutput
-	versionCmd := exec.Command(tfInfo.Binary, "version", "-json")
-	versionCmd.Dir = workingDir
-	versionOutput, err := versionCmd.Output()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to get terraform version info: %v\n", err)
-	} else {
-		var version schema.VersionOutput
-		if err := json.Unmarshal(versionOutput, &version); err == nil {
-			versionInfo = &version
-		}
+	if versionErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to get terraform version info: %v\n", versionErr)
 	}
 
 	// Create the combined schema with version info
@@ -61,9 +89,32 @@ func FetchAllProviderSchemas(workingDir string) (*SchemaWithVersionInfo, error)
 		TfInfo:      tfInfo,
 	}
 
-	if err := WriteProviderSchemaToCache(workingDir, schemaWithVersion); err != nil {
+	switch {
+	case len(selections) > 0:
+		err = writeProvidersToCache(keys, &providerSchemas)
+	case idErr == nil:
+		// Without a lockfile there are no versions to key providers by
+		err = WriteProviderSchemaToCache(id, schemaWithVersion)
+	default:
+		err = idErr
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cache schema: %v\n", err)
 	}
 
 	return schemaWithVersion, nil
 }
+
+// DetectVersion runs `<tool> version -json` and returns the parsed version information
+func DetectVersion(tfInfo TerraformInfo, workingDir string) (*schema.VersionOutput, error) {
+	versionOutput, err := runCommand(tfInfo, workingDir, "version", "-json")
+	if err != nil {
+		return nil, err
+	}
+
+	var version schema.VersionOutput
+	if err := json.Unmarshal(versionOutput, &version); err != nil {
+		return nil, fmt.Errorf("failed to parse version output: %w", err)
+	}
+	return &version, nil
+}
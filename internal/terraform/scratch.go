@@ -0,0 +1,107 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+)
+
+// providerSourcePattern matches [<host>/]<namespace>/<type> provider source addresses
+var providerSourcePattern = regexp.MustCompile(`^([a-zA-Z0-9.-]+/)?[a-zA-Z0-9-]+/[a-zA-Z0-9-]+$`)
+
+func checkProviderSource(source string) error {
+	if !providerSourcePattern.MatchString(source) {
+		return fmt.Errorf("invalid provider source %q, expected <namespace>/<type> or <host>/<namespace>/<type>", source)
+	}
+	return nil
+}
+
+// ProviderSourceAddress returns the full source address of a provider source as written
+// in required_providers, adding the tool's default registry host when none is given
+func ProviderSourceAddress(source string, tfInfo TerraformInfo) (string, error) {
+	if err := checkProviderSource(source); err != nil {
+		return "", err
+	}
+	source = strings.ToLower(source)
+	if strings.Count(source, "/") == 1 {
+		registry := tfInfo.Registry
+		if registry == "" {
+			registry = registryForTool(tfInfo.Tool)
+		}
+		source = registry + "/" + source
+	}
+	return source, nil
+}
+
+// ExactVersion returns the version a constraint pins, e.g. 6.0.0 for "6.0.0" or "= 6.0.0",
+// and false for ranges and empty constraints
+func ExactVersion(constraint string) (string, bool) {
+	constraint = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(constraint), "="))
+	if constraint == "" || strings.ContainsAny(constraint, "<>~!,|* ") {
+		return "", false
+	}
+	v, err := semver.StrictNewVersion(constraint)
+	if err != nil {
+		return "", false
+	}
+	return v.String(), true
+}
+
+// CreateScratchWorkspace writes a throwaway working directory whose only configuration
+// is a required_providers entry for source, limited to constraint when it is not empty.
+// The caller removes the directory when done.
+func CreateScratchWorkspace(source, constraint string) (string, error) {
+	if err := checkProviderSource(source); err != nil {
+		return "", err
+	}
+	localName := strings.ToLower(source[strings.LastIndex(source, "/")+1:])
+
+	var config strings.Builder
+	config.WriteString("terraform {\n  required_providers {\n")
+	fmt.Fprintf(&config, "    %s = {\n      source = %q\n", localName, source)
+	if constraint != "" {
+		fmt.Fprintf(&config, "      version = %q\n", constraint)
+	}
+	config.WriteString("    }\n  }\n}\n")
+
+	dir, err := os.MkdirTemp("", "provider-explorer-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create workspace: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config.String()), 0644); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to write workspace configuration: %w", err)
+	}
+	return dir, nil
+}
+
+// ReadCachedProviderSchema returns the schema of one provider version from the
+// per-provider cache, as populated by any earlier fetch of that version. A released
+// provider version never changes, so the result needs no revalidation.
+func ReadCachedProviderSchema(source, version string, tfInfo TerraformInfo) (*SchemaWithVersionInfo, error) {
+	ps, err := readProviderFromCache(source, version)
+	if err != nil {
+		return nil, err
+	}
+
+	versionInfo := &schema.VersionOutput{}
+	if detected, err := DetectVersion(tfInfo, "."); err == nil {
+		versionInfo = detected
+	}
+	versionInfo.ProviderSelections = map[string]string{source: version}
+
+	return &SchemaWithVersionInfo{
+		Schemas: &schema.ProviderSchemas{
+			FormatVersion: "1.0",
+			Schemas:       map[string]*schema.ProviderSchema{source: ps},
+		},
+		VersionInfo: versionInfo,
+		TfInfo:      tfInfo,
+	}, nil
+}
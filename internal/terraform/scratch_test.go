@@ -0,0 +1,76 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+)
+
+func TestProviderSourceAddress(t *testing.T) {
+	tofu := TerraformInfo{Tool: "tofu", Registry: "registry.opentofu.org"}
+
+	source, err := ProviderSourceAddress("hashicorp/AWS", tofu)
+	require.NoError(t, err)
+	require.Equal(t, "registry.opentofu.org/hashicorp/aws", source)
+
+	source, err = ProviderSourceAddress("example.com/acme/widget", tofu)
+	require.NoError(t, err)
+	require.Equal(t, "example.com/acme/widget", source)
+
+	for _, invalid := range []string{"aws", "hashicorp/aws/extra/part", "hashicorp/aws@6.0.0", ""} {
+		_, err := ProviderSourceAddress(invalid, tofu)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestExactVersion(t *testing.T) {
+	for constraint, want := range map[string]string{
+		"6.0.0":   "6.0.0",
+		"= 6.0.0": "6.0.0",
+		"=6.1.2":  "6.1.2",
+		"~> 6.0":  "",
+		">= 6.0":  "",
+		"6.0":     "",
+		"":        "",
+	} {
+		got, ok := ExactVersion(constraint)
+		require.Equal(t, want, got, constraint)
+		require.Equal(t, want != "", ok, constraint)
+	}
+}
+
+func TestCreateScratchWorkspace(t *testing.T) {
+	dir, err := CreateScratchWorkspace("hashicorp/aws", "~> 6.0")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	specs, err := getProviderSpecs(dir)
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	require.Equal(t, "hashicorp/aws", specs[0].Source)
+	require.Equal(t, "~> 6.0", specs[0].Constraints)
+
+	_, err = CreateScratchWorkspace("not a source", "")
+	require.Error(t, err)
+}
+
+func TestReadCachedProviderSchema(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tfInfo := TerraformInfo{Binary: filepath.Join(t.TempDir(), "missing"), Tool: "terraform"}
+
+	_, err := ReadCachedProviderSchema("registry.terraform.io/hashicorp/aws", "6.0.0", tfInfo)
+	require.Error(t, err)
+
+	require.NoError(t, writeProviderToCache("registry.terraform.io/hashicorp/aws", "6.0.0", &schema.ProviderSchema{
+		ResourceSchemas: map[string]*schema.Schema{"aws_s3_bucket": {}},
+	}))
+	result, err := ReadCachedProviderSchema("registry.terraform.io/hashicorp/aws", "6.0.0", tfInfo)
+	require.NoError(t, err)
+	require.Contains(t, result.Schemas.Schemas["registry.terraform.io/hashicorp/aws"].ResourceSchemas, "aws_s3_bucket")
+	require.Equal(t, map[string]string{"registry.terraform.io/hashicorp/aws": "6.0.0"}, result.VersionInfo.ProviderSelections)
+	require.False(t, result.FromCache)
+}
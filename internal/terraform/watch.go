@@ -0,0 +1,114 @@
+package terraform
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultWatchInterval is how often WatchWorkspace polls the working directory
+const DefaultWatchInterval = time.Second
+
+// WatchWorkspace polls a working directory for changes that affect its provider schemas:
+// the lockfile, the installed providers and the *.tf / *.tf.json configuration files.
+// A value is sent on the returned channel once a change has settled, i.e. after two
+// consecutive polls saw the same new state, so a running init reports once it is done
+// writing rather than on every file. The channel is closed when ctx is cancelled.
+func WatchWorkspace(ctx context.Context, workingDir string, interval time.Duration) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := workspaceFingerprint(workingDir)
+		pending := ""
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current := workspaceFingerprint(workingDir)
+			switch {
+			case current == last:
+				pending = ""
+			case current != pending:
+				// Changed since the last poll; wait for it to settle
+				pending = current
+			default:
+				last = current
+				pending = ""
+				select {
+				case changes <- struct{}{}:
+				default:
+					// A change is already queued for the consumer
+				}
+			}
+		}
+	}()
+
+	return changes
+}
+
+// workspaceFingerprint hashes the names, sizes and modification times of the files
+// that determine the provider schemas of a working directory
+func workspaceFingerprint(workingDir string) string {
+	var paths []string
+	for _, pattern := range []string{"*.tf", "*.tf.json"} {
+		matches, _ := filepath.Glob(filepath.Join(workingDir, pattern))
+		paths = append(paths, matches...)
+	}
+	paths = append(paths, filepath.Join(workingDir, ".terraform.lock.hcl"))
+
+	absDir, err := filepath.Abs(workingDir)
+	if err == nil {
+		providersDir := filepath.Join(DataDir(absDir), "providers")
+		filepath.WalkDir(providersDir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		info, err := os.Lstat(path)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// InvalidateCache removes the cached schema document of a working directory so the next
+// load asks the tool again. Per-provider entries stay: they are keyed by provider
+// version, so a lockfile change selects different entries rather than stale ones.
+func InvalidateCache(workingDir string, tfInfo TerraformInfo) error {
+	var toolVersion string
+	if versionInfo, err := DetectVersion(tfInfo, workingDir); err == nil {
+		toolVersion = versionInfo.Version
+	}
+	id, err := NewCacheIdentity(workingDir, tfInfo, toolVersion)
+	if err != nil {
+		return err
+	}
+	cachePath, err := getCacheFilePath(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(cachePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", cachePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchWorkspace_ReportsSettledChanges(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte("# empty\n"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := WatchWorkspace(ctx, dir, 10*time.Millisecond)
+
+	// Files that do not affect schemas are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("notes\n"), 0644))
+	select {
+	case <-changes:
+		t.Fatal("unexpected change for README.md")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	for _, path := range []string{
+		filepath.Join(dir, "versions.tf"),
+		filepath.Join(dir, ".terraform.lock.hcl"),
+		filepath.Join(dir, ".terraform", "providers", "registry.terraform.io", "hashicorp", "null", "3.2.4", "linux_amd64", "terraform-provider-null"),
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("changed\n"), 0644))
+		select {
+		case <-changes:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no change reported for %s", path)
+		}
+	}
+
+	cancel()
+	for range changes {
+		// Drain until the watcher closes the channel
+	}
+}
@@ -1,11 +1,15 @@
 package ui
 
 import (
+	"context"
+
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/terraconstructs/provider-explorer/internal/config"
 	"github.com/terraconstructs/provider-explorer/internal/terraform"
 	"strings"
 	"time"
@@ -44,14 +48,22 @@ const (
 	StageEntityBrowse
 	StageTreeView
 	StageExportResult
+	StageError
+	StageInitConfirm
+	StageInitRunning
+	StageWorkspaceSelect
 )
 
 // schemaLoadedMsg is sent when schemas are loaded
 type schemaLoadedMsg struct {
-	schemas  *tfjson.ProviderSchemas
-	toolInfo terraform.TerraformInfo
-	version  string
-	err      error
+	schemas    *tfjson.ProviderSchemas
+	toolInfo   terraform.TerraformInfo
+	version    string
+	versions   map[string]string // selected provider versions, when known
+	modules    []config.Module   // modules of the working directory, when loaded from one
+	modulesErr error             // why the modules could not be read
+	fromCache  bool
+	err        error
 }
 
 // exportRequestMsg is sent when user requests export
@@ -85,6 +97,7 @@ type Model struct {
 	stage    AppStage
 	focus    FocusArea
 	schemas  *tfjson.ProviderSchemas
+	modules  []config.Module
 	toolInfo terraform.TerraformInfo
 	version  string
 
@@ -97,13 +110,41 @@ type Model struct {
 	exportResult   string
 	exportViewport viewport.Model
 	showHelp       bool
+	showDetails    bool // details of the node under the cursor below the tree
 
 	// Export (attributes) name prompt state
 	exportNamePrompt bool
 	exportName       string
 
 	// Test/control flags
-	disableAutoLoad bool // when true, Init will not trigger schema loading
+	disableAutoLoad bool    // when true, Init will not trigger schema loading
+	loadCmd         tea.Cmd // overrides the default schema loading when set
+
+	// Launch target applied once schemas are loaded
+	deepLink *DeepLink
+
+	// Load failure shown by the error stage
+	loadErr     error
+	errorNotice string // result of the last failed error-stage action
+
+	// Init run inside the TUI
+	initAutoStart bool // start init without confirmation (--yes)
+	initProc      *terraform.InitProcess
+	initCancel    context.CancelFunc
+	initLines     []string
+
+	// Background revalidation of schemas opened from the cache
+	providerVersions map[string]string
+	pendingRefresh   *schemaLoadedMsg // refreshed schemas waiting for the user to reload
+
+	// Workspace changes reported by the watcher (--watch)
+	watchCh <-chan struct{}
+
+	// Root modules of a monorepo offered by the workspace picker
+	workspaceRoot     string
+	workspaceList     list.Model
+	workspaceAutoInit bool   // initialize picked workspaces without confirmation (--yes)
+	workspace         string // picked workspace, relative to workspaceRoot
 }
 
 // NewModel creates a new application model
@@ -131,24 +172,33 @@ func NewModel(width, height int) Model {
 }
 
 // loadSchemaCmd loads the provider schemas
-func loadSchemaCmd(workingDir string) tea.Cmd {
+func loadSchemaCmd(workingDir string, tfInfo terraform.TerraformInfo) tea.Cmd {
 	return func() tea.Msg {
-		schemaWithVersion, err := terraform.FetchAllProviderSchemas(workingDir)
+		if tfInfo.Binary == "" {
+			tfInfo = terraform.FindTerraformBinary()
+		}
+		schemaWithVersion, err := terraform.FetchAllProviderSchemas(workingDir, tfInfo)
 		if err != nil {
 			return schemaLoadedMsg{err: err}
 		}
+		msg := newSchemaLoadedMsg(schemaWithVersion)
+		msg.modules, msg.modulesErr = config.LoadModules(workingDir)
+		return msg
+	}
+}
 
-		var version string
-		if schemaWithVersion.VersionInfo != nil {
-			version = schemaWithVersion.VersionInfo.Version
-		}
-
-		return schemaLoadedMsg{
-			schemas:  schemaWithVersion.Schemas,
-			toolInfo: schemaWithVersion.TfInfo,
-			version:  version,
-		}
+// newSchemaLoadedMsg converts a fetch result into a schemaLoadedMsg
+func newSchemaLoadedMsg(schemaWithVersion *terraform.SchemaWithVersionInfo) schemaLoadedMsg {
+	msg := schemaLoadedMsg{
+		schemas:   schemaWithVersion.Schemas,
+		toolInfo:  schemaWithVersion.TfInfo,
+		fromCache: schemaWithVersion.FromCache,
 	}
+	if v := schemaWithVersion.VersionInfo; v != nil {
+		msg.version = v.Version
+		msg.versions = v.ProviderSelections
+	}
+	return msg
 }
 
 // Init initializes the model
@@ -157,7 +207,26 @@ func (m Model) Init() tea.Cmd {
 		// Used by tests that inject schemas directly
 		return nil
 	}
-	return loadSchemaCmd(".")
+	var cmd tea.Cmd
+	switch {
+	case m.stage == StageInitConfirm:
+		// Wait for the user to confirm init
+	case m.stage == StageWorkspaceSelect:
+		// Wait for the user to pick a workspace
+	case m.initAutoStart:
+		cmd = func() tea.Msg { return initStartMsg{} }
+	default:
+		cmd = m.schemaLoadCmd()
+	}
+	if m.watchCh != nil {
+		cmd = tea.Batch(cmd, waitForWorkspaceChange(m.watchCh))
+	}
+	return cmd
+}
+
+// SetToolInfo selects the terraform/tofu binary used to load schemas
+func (m *Model) SetToolInfo(toolInfo terraform.TerraformInfo) {
+	m.toolInfo = toolInfo
 }
 
 // Update handles messages
@@ -180,12 +249,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				entityName, entitySchema := m.entities.SelectedEntity()
 				if entitySchema != nil {
 					selected := m.filteredSelectedPaths(m.tree.GetSelectedPaths())
-					m.exportResult = ConvertSelectedAttributesToHCLOutputs(entityName, entitySchema, m.selectedProvider, m.exportName, selected)
-					m.exportViewport.SetContent(m.exportResult)
-					m.exportViewport.GotoTop()
-					m.stage = StageExportResult
-					m.status.SetHelpText("j/k scroll • c copy • esc return")
-					m.tree.Blur()
+					m.showExportResult(ConvertSelectedAttributesToHCLOutputs(entityName, entitySchema, m.selectedProvider, m.exportName, selected))
 				}
 				m.exportNamePrompt = false
 				return m, nil
@@ -226,12 +290,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case schemaLoadedMsg:
 		if msg.err != nil {
-			// TODO: Handle error better
-			return m, tea.Quit
+			m.showLoadError(msg.err)
+			return m, nil
 		}
 		m.schemas = msg.schemas
 		m.toolInfo = msg.toolInfo
 		m.version = msg.version
+		m.providerVersions = msg.versions
+		m.pendingRefresh = nil
+		m.status.ClearNotice()
+		m.setModules(msg.modules)
+		m.noteModulesError(msg.modulesErr)
 
 		// Update components with loaded data
 		m.providers.SetSchemas(msg.schemas)
@@ -271,6 +340,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.providers.Focus()
 		}
 
+		m.applyDeepLink()
+
+		if msg.fromCache {
+			// Show the cached schemas right away and check them against the tool
+			cmds = append(cmds, refreshSchemaCmd(m.workingDir(), m.toolInfo, m.schemas, m.providerVersions))
+		}
+
+	case schemaRefreshMsg:
+		m.handleSchemaRefresh(msg)
+		return m, nil
+
+	case workspaceChangedMsg:
+		return m, m.handleWorkspaceChanged()
+
+	case workspaceReloadedMsg:
+		return m, m.handleWorkspaceReloaded(msg)
+
+	case initStartMsg:
+		return m, m.startInit()
+
+	case initOutputMsg:
+		m.initLines = append(m.initLines, msg.line)
+		return m, waitForInitOutput(m.initProc)
+
+	case initFinishedMsg:
+		m.finishInit()
+		if msg.err != nil {
+			m.showLoadError(msg.err)
+			return m, nil
+		}
+		return m, m.retryLoad()
+
 	case copyStatusMsg:
 		// Clear the copy status message
 		m.status.ClearCopyStatus()
@@ -287,6 +388,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 
 	case tea.KeyMsg:
+		if m.stage == StageWorkspaceSelect {
+			return m, m.handleWorkspaceKey(msg)
+		}
+		if m.stage == StageInitConfirm || m.stage == StageInitRunning {
+			return m, m.handleInitKey(msg)
+		}
+		if m.stage == StageError {
+			if cmd, handled := m.handleErrorKey(msg); handled {
+				return m, cmd
+			}
+			if s := msg.String(); s == "ctrl+c" || s == "q" {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
 		// Direct tree navigation keys when tree is focused
 		if m.stage == StageTreeView && m.focus == FocusTree {
 			switch msg.String() {
@@ -302,6 +419,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "pgup":
 				m.tree.treeModel.MovePageUp()
 				return m, nil
+			case "d":
+				m.showDetails = !m.showDetails
+				m.updateLayout()
+				return m, nil
+			case "y":
+				return m, m.handleCopyReference()
 			case "esc":
 				// If there is any selection, clear it first; otherwise, go back
 				if len(m.tree.GetSelectedPaths()) > 0 {
@@ -336,6 +459,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "?":
 			m.showHelp = !m.showHelp
 			return m, nil
+		case "R":
+			if m.pendingRefresh != nil && !(m.focus == FocusEntities && m.entities.IsFilterFocused()) {
+				if !m.applyRefresh() {
+					return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+						return copyStatusMsg{}
+					})
+				}
+				return m, nil
+			}
 		case "tab":
 			return m, m.handleTabNavigation()
 		case "enter":
@@ -411,7 +543,12 @@ func (m *Model) updateLayout() {
 		}
 
 		m.entities.SetSize(halfWidth, fullHeight)
-		m.tree.SetSize(halfWidth, fullHeight)
+		if m.showDetails {
+			treeHeight, _ := detailsPaneHeights(fullHeight)
+			m.tree.SetSize(halfWidth, treeHeight)
+		} else {
+			m.tree.SetSize(halfWidth, fullHeight)
+		}
 	} else {
 		// Navigation view: providers+types stacked on left, entities on right (50-50 split)
 		// Account for borders (2 chars per side = 4 chars total per border)
@@ -420,7 +557,7 @@ func (m *Model) updateLayout() {
 		fullHeight := m.height - 4 // Reserve space for status bar
 
 		// Calculate heights for left column - account for list titles and vertical spacing
-		typesHeight := 8 // Types list (4 items + title + borders)
+		typesHeight := 4 + m.types.Len() // Types list (items + title + borders)
 		// Account for spacing between stacked components and list titles
 		verticalSpacing := 1 // Space between providers and types panes
 		providersHeight := fullHeight - typesHeight - verticalSpacing
@@ -556,6 +693,8 @@ func (m *Model) handleEnter() tea.Cmd {
 					typeName = "Ephemeral Resources"
 				case ProviderFunctionsType:
 					typeName = "Provider Functions"
+				case ModulesType:
+					typeName = "Modules"
 				}
 				m.status.SetResourceType(typeName)
 			}
@@ -568,6 +707,7 @@ func (m *Model) handleEnter() tea.Cmd {
 	case FocusEntities:
 		if entityName, entitySchema := m.entities.SelectedEntity(); entityName != "" {
 			m.selectedEntity = entityName
+			m.tree.SetModule(m.entities.SelectedModule())
 			m.tree.SetSchema(entityName, entitySchema)
 
 			// Transition to tree view stage
@@ -591,6 +731,11 @@ func (m *Model) handleEnter() tea.Cmd {
 // handleEscape handles escape key for going back
 func (m *Model) handleEscape() tea.Cmd {
 	switch m.stage {
+	case StageProviderSelect:
+		if m.hasWorkspaces() {
+			m.showWorkspacePicker()
+		}
+
 	case StageTypeSelect:
 		m.stage = StageProviderSelect
 		m.focus = FocusProviders
@@ -646,15 +791,23 @@ func (m *Model) handleExport() tea.Cmd {
 	// Generate HCL based on tree mode
 	switch m.tree.GetMode() {
 	case ArgumentsMode:
-		// Export only selected argument attributes
-		m.exportResult = ConvertSelectedArgumentsToHCLVariables(entitySchema, m.filteredSelectedPaths(selectedPaths))
-		m.exportViewport.SetContent(m.exportResult)
-		m.exportViewport.GotoTop()
-		m.stage = StageExportResult
-		m.status.SetHelpText("j/k scroll • c copy • esc return")
-		m.tree.Blur()
+		// Export only selected argument attributes, with the defaults of module variables
+		var defaults map[string]string
+		if module := m.entities.SelectedModule(); module != nil {
+			defaults = module.defaultExpressions()
+		}
+		m.showExportResult(convertSelectedArgumentsToHCLVariables(entitySchema, m.filteredSelectedPaths(selectedPaths), defaults))
 		return nil
 	case AttributesMode:
+		if module := m.entities.SelectedModule(); module != nil {
+			// Module outputs are referenced through the module call, which is already named
+			if !module.module.RootCall() {
+				return m.refuseNestedModule(module.module)
+			}
+			selected := m.filteredSelectedPaths(selectedPaths)
+			m.showExportResult(ConvertSelectedAttributesToHCLOutputs("module", module.outputs, m.selectedProvider, module.module.Name(), selected))
+			return nil
+		}
 		// Prompt for resource instance name before exporting
 		m.exportNamePrompt = true
 		m.exportName = "main"
@@ -664,6 +817,16 @@ func (m *Model) handleExport() tea.Cmd {
 	return nil
 }
 
+// showExportResult switches to the export stage showing the generated HCL
+func (m *Model) showExportResult(result string) {
+	m.exportResult = result
+	m.exportViewport.SetContent(m.exportResult)
+	m.exportViewport.GotoTop()
+	m.stage = StageExportResult
+	m.status.SetHelpText("j/k scroll • c copy • esc return")
+	m.tree.Blur()
+}
+
 // handleCopy handles copy to clipboard
 func (m *Model) handleCopy() tea.Cmd {
 	return func() tea.Msg {
@@ -674,6 +837,24 @@ func (m *Model) handleCopy() tea.Cmd {
 	}
 }
 
+// handleCopyReference copies the reference of the tree node under the cursor
+func (m *Model) handleCopyReference() tea.Cmd {
+	node := m.tree.CurrentNode()
+	if node == nil {
+		return nil
+	}
+	reference := m.nodeReference(node)
+	if reference == "" {
+		return m.refuseNestedModule(m.entities.SelectedModule().module)
+	}
+	return func() tea.Msg {
+		if err := CopyToClipboard(reference); err != nil {
+			return copyResultMsg{success: false, err: err}
+		}
+		return copyResultMsg{success: true, err: nil}
+	}
+}
+
 // GetSchemas returns the loaded schemas (for testing)
 func (m Model) GetSchemas() *tfjson.ProviderSchemas {
 	return m.schemas
@@ -689,6 +870,22 @@ func (m Model) View() string {
 		return m.renderLoadingView()
 	}
 
+	if m.stage == StageError {
+		return m.renderErrorView()
+	}
+
+	if m.stage == StageWorkspaceSelect {
+		return m.renderWorkspaceView()
+	}
+
+	if m.stage == StageInitConfirm {
+		return m.renderInitConfirmView()
+	}
+
+	if m.stage == StageInitRunning {
+		return m.renderInitRunningView()
+	}
+
 	if m.stage == StageExportResult {
 		return m.renderExportView()
 	}
@@ -730,7 +927,7 @@ func (m Model) renderNavigationView() string {
 	fullHeight := m.height - 4 // Reserve space for status bar
 
 	// Calculate heights matching updateLayout logic
-	typesHeight := 8
+	typesHeight := 4 + m.types.Len()
 	verticalSpacing := 1
 	providersHeight := fullHeight - typesHeight - verticalSpacing
 	entitiesHeight := providersHeight + typesHeight + verticalSpacing
@@ -816,9 +1013,19 @@ func (m Model) renderTreeView() string {
 		innerHeight = 1
 	}
 
+	// The details pane, when shown, takes the lower part of the tree column
+	treeHeight, detailsHeight := fullHeight, 0
+	if m.showDetails {
+		treeHeight, detailsHeight = detailsPaneHeights(fullHeight)
+	}
+	treeInnerHeight := treeHeight - 2 /*borders*/
+	if treeInnerHeight < 1 {
+		treeInnerHeight = 1
+	}
+
 	// Ensure components use inner dimensions so titles and borders align
 	m.entities.SetSize(innerWidth, innerHeight)
-	m.tree.SetSize(innerWidth, innerHeight)
+	m.tree.SetSize(innerWidth, treeInnerHeight)
 
 	// Left pane - entities with focus border and explicit size enforcement
 	entitiesView := m.entities.View()
@@ -831,9 +1038,14 @@ func (m Model) renderTreeView() string {
 	// Right pane - tree with focus border and explicit size enforcement
 	treeView := m.tree.View()
 	if m.focus == FocusTree {
-		treeView = focusedBorderStyle.Width(halfWidth).Height(fullHeight).Render(treeView)
+		treeView = focusedBorderStyle.Width(halfWidth).Height(treeHeight).Render(treeView)
 	} else {
-		treeView = unfocusedBorderStyle.Width(halfWidth).Height(fullHeight).Render(treeView)
+		treeView = unfocusedBorderStyle.Width(halfWidth).Height(treeHeight).Render(treeView)
+	}
+	if m.showDetails {
+		detailsView := m.renderDetails(innerWidth, detailsHeight)
+		detailsView = unfocusedBorderStyle.Width(halfWidth).Height(detailsHeight).Render(detailsView)
+		treeView = lipgloss.JoinVertical(lipgloss.Left, treeView, detailsView)
 	}
 
 	// Join entities and tree horizontally
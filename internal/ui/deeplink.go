@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+)
+
+// DeepLink describes where the explorer should open once schemas are loaded.
+// Empty fields stop the walk at the previous stage, except Provider which may be
+// omitted when the workspace has a single provider.
+type DeepLink struct {
+	Provider string       // short, namespaced or full provider address
+	Type     ResourceType // category holding Entity
+	Entity   string
+	Path     string // dotted attribute path within Entity
+}
+
+// SetDeepLink makes the model open directly on a provider, entity and attribute path
+func (m *Model) SetDeepLink(link DeepLink) {
+	m.deepLink = &link
+}
+
+// applyDeepLink drives the regular enter flow from the current stage to the linked
+// entity and places the tree cursor on the linked path. It stops at the first part
+// of the link that cannot be resolved and reports it in the status bar.
+func (m *Model) applyDeepLink() {
+	link := m.deepLink
+	m.deepLink = nil
+	if link == nil || (link.Provider == "" && link.Entity == "") {
+		return
+	}
+
+	providerName := m.selectedProvider
+	if link.Provider != "" {
+		var err error
+		if providerName, err = schema.ResolveProviderName(m.schemas, link.Provider); err != nil {
+			m.status.SetCopyStatus("✗ "+err.Error(), "error")
+			return
+		}
+	} else if m.stage == StageProviderSelect {
+		m.status.SetCopyStatus("✗ several providers are installed, use --provider", "error")
+		return
+	}
+
+	// A single provider is auto-selected on load; otherwise select it here
+	if m.stage == StageProviderSelect {
+		m.providers.SelectProvider(providerName)
+		m.handleEnter()
+	} else if m.selectedProvider != providerName {
+		m.status.SetCopyStatus(fmt.Sprintf("✗ provider %s not found", link.Provider), "error")
+		return
+	}
+
+	if link.Entity == "" {
+		return
+	}
+	if !m.types.SelectType(link.Type) {
+		m.status.SetCopyStatus("✗ entity type not available for this provider", "error")
+		return
+	}
+	m.handleEnter()
+
+	if !m.entities.SelectEntity(link.Entity) {
+		m.status.SetCopyStatus(fmt.Sprintf("✗ %s not found in %s", link.Entity, providerName), "error")
+		return
+	}
+	m.handleEnter()
+
+	if link.Path == "" {
+		return
+	}
+	if !m.tree.FocusPath(strings.Split(link.Path, ".")) {
+		m.status.SetCopyStatus(fmt.Sprintf("✗ path %s not found in %s", link.Path, link.Entity), "error")
+	}
+}
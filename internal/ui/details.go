@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
+	"github.com/terraconstructs/provider-explorer/internal/ui/tree"
+)
+
+// referenceInstanceName names the instance in the references shown for tree nodes,
+// matching the default of the export name prompt
+const referenceInstanceName = "main"
+
+var (
+	detailsTitleStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("212"))
+
+	detailsLabelStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("244"))
+
+	detailsWarningStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("208")).
+				Bold(true)
+)
+
+// detailsPaneHeights splits the height of the tree column between the tree and the
+// details pane below it, leaving room for the border of the extra pane
+func detailsPaneHeights(height int) (treeHeight, detailsHeight int) {
+	detailsHeight = height * 2 / 5
+	return height - detailsHeight - 2, detailsHeight
+}
+
+// nodeReference returns the expression referencing a tree node from configuration,
+// e.g. aws_instance.main.root_block_device.volume_size, or var.name and module.app.url
+// for the variables and outputs of a module. The outputs of a module called by another
+// module have no reference from the root module, so they get none.
+func (m Model) nodeReference(node *tree.SchemaNode) string {
+	base := m.tree.entity + "." + referenceInstanceName
+	if module := m.entities.SelectedModule(); module != nil {
+		base = "module." + module.module.Name()
+		if m.tree.GetMode() == ArgumentsMode {
+			base = "var"
+		} else if !module.module.RootCall() {
+			return ""
+		}
+	} else {
+		switch m.selectedType {
+		case DataSourcesType:
+			base = "data." + base
+		case EphemeralResourcesType:
+			base = "ephemeral." + base
+		}
+	}
+	return attributeReference(base, m.tree.modeSchema().Block, node.GetPath())
+}
+
+// renderDetails renders everything the schema tells about the tree node under the cursor
+func (m Model) renderDetails(width, height int) string {
+	node := m.tree.CurrentNode()
+	if node == nil {
+		return detailsTitleStyle.Render("Details") + "\n" + detailsLabelStyle.Render("Nothing under the cursor")
+	}
+
+	var lines []string
+	field := func(label, value string) {
+		if value != "" {
+			lines = append(lines, detailsLabelStyle.Render(label+": ")+value)
+		}
+	}
+	reference := func(node *tree.SchemaNode) {
+		if ref := m.nodeReference(node); ref != "" {
+			field("Reference", ref+detailsLabelStyle.Render(" (y to copy)"))
+		}
+	}
+
+	var description string
+	var descriptionKind tfjson.SchemaDescriptionKind
+	var deprecated bool
+	if attr := node.GetAttribute(); attr != nil {
+		kind := "attribute"
+		if attr.AttributeNestedType != nil {
+			kind = "nested attribute"
+		}
+		lines = append(lines, detailsTitleStyle.Render(node.GetName())+" "+detailsLabelStyle.Render(kind))
+		field("Path", strings.Join(node.GetPath(), "."))
+		reference(node)
+		field("Type", schema.HCLType(schema.AttributeType(attr)))
+		field("Flags", strings.Join(attributeFlags(attr), ", "))
+		if module := m.entities.SelectedModule(); module != nil && m.tree.GetMode() == ArgumentsMode && len(node.GetPath()) == 1 {
+			if def, ok := module.defaults[node.GetName()]; ok {
+				field("Default", renderDefault(def))
+			}
+		}
+		description, descriptionKind, deprecated = attr.Description, attr.DescriptionKind, attr.Deprecated
+	} else if blockType := node.GetBlockType(); blockType != nil {
+		lines = append(lines, detailsTitleStyle.Render(node.GetName())+" "+detailsLabelStyle.Render("block"))
+		field("Path", strings.Join(node.GetPath(), "."))
+		reference(node)
+		field("Type", schema.HCLType(schema.BlockType(blockType)))
+		field("Nesting", schema.BlockLabel(blockType))
+		if blockType.Block != nil {
+			description, descriptionKind, deprecated = blockType.Block.Description, blockType.Block.DescriptionKind, blockType.Block.Deprecated
+		}
+	}
+
+	if deprecated {
+		lines = append(lines, detailsWarningStyle.Render("Deprecated: avoid in new configuration"))
+	}
+	if description != "" {
+		lines = append(lines, "")
+		if descriptionKind == tfjson.SchemaDescriptionKindMarkdown {
+			lines = append(lines, renderMarkdown(description, width))
+		} else {
+			lines = append(lines, description)
+		}
+	}
+
+	return lipgloss.NewStyle().Width(width).MaxHeight(height).Render(strings.Join(lines, "\n"))
+}
+
+// attributeFlags lists how an attribute may be set and how its value is treated
+func attributeFlags(attr *tfjson.SchemaAttribute) []string {
+	var flags []string
+	switch {
+	case attr.Required:
+		flags = append(flags, "required")
+	case attr.Optional && attr.Computed:
+		flags = append(flags, "optional", "computed")
+	case attr.Optional:
+		flags = append(flags, "optional")
+	case attr.Computed:
+		flags = append(flags, "computed")
+	}
+	if attr.Sensitive {
+		flags = append(flags, "sensitive")
+	}
+	if attr.WriteOnly {
+		flags = append(flags, "write-only")
+	}
+	if attr.Deprecated {
+		flags = append(flags, "deprecated")
+	}
+	return flags
+}
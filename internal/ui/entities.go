@@ -9,6 +9,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/terraconstructs/provider-explorer/internal/config"
 )
 
 var (
@@ -36,6 +38,7 @@ var (
 type EntityItem struct {
 	name   string
 	schema *tfjson.Schema
+	module *moduleEntity // set for the entities of the Modules category
 }
 
 // FilterValue implements list.Item
@@ -46,6 +49,9 @@ func (i EntityItem) Title() string { return i.name }
 
 // Description returns a description of the entity
 func (i EntityItem) Description() string {
+	if i.module != nil {
+		return i.module.summary()
+	}
 	if i.schema == nil || i.schema.Block == nil {
 		return "No schema available"
 	}
@@ -102,6 +108,7 @@ type EntitiesModel struct {
 	currentType    ResourceType
 	provider       string
 	providerSchema *tfjson.ProviderSchema
+	modules        []config.Module
 }
 
 // NewEntitiesModel creates a new entities model
@@ -135,6 +142,12 @@ func (m *EntitiesModel) SetProvider(providerName string, schema *tfjson.Provider
 	m.rebuildList()
 }
 
+// SetModules updates the modules of the workspace listed by the Modules category
+func (m *EntitiesModel) SetModules(modules []config.Module) {
+	m.modules = modules
+	m.rebuildList()
+}
+
 // SetType updates the resource type and rebuilds the list
 func (m *EntitiesModel) SetType(resType ResourceType) {
 	m.currentType = resType
@@ -150,11 +163,23 @@ func (m *EntitiesModel) SetType(resType ResourceType) {
 		m.list.Title = "Ephemeral Resources"
 	case ProviderFunctionsType:
 		m.list.Title = "Provider Functions"
+	case ModulesType:
+		m.list.Title = "Modules"
 	}
 }
 
 // rebuildList rebuilds the entity list based on current provider and type
 func (m *EntitiesModel) rebuildList() {
+	// Modules belong to the workspace rather than to the provider
+	if m.currentType == ModulesType {
+		items := make([]list.Item, len(m.modules))
+		for i, mod := range m.modules {
+			items[i] = newModuleItem(mod)
+		}
+		m.list.SetItems(items)
+		return
+	}
+
 	if m.providerSchema == nil {
 		m.list.SetItems([]list.Item{})
 		return
@@ -273,6 +298,26 @@ func (m EntitiesModel) SelectedEntity() (string, *tfjson.Schema) {
 	return "", nil
 }
 
+// SelectedModule returns the module behind the selected entity, or nil outside the
+// Modules category
+func (m EntitiesModel) SelectedModule() *moduleEntity {
+	if item, ok := m.list.SelectedItem().(EntityItem); ok {
+		return item.module
+	}
+	return nil
+}
+
+// SelectEntity moves the list cursor to the named entity
+func (m *EntitiesModel) SelectEntity(name string) bool {
+	for i, item := range m.list.Items() {
+		if e, ok := item.(EntityItem); ok && e.name == name {
+			m.list.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
 // Update handles messages for the entities model
 func (m EntitiesModel) Update(msg tea.Msg) (EntitiesModel, tea.Cmd) {
 	if !m.focused {
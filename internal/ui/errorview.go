@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+var (
+	errorTitleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("9"))
+
+	errorLabelStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("212")).
+			Bold(true)
+
+	errorOutputStyle = lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(lipgloss.Color("240")).
+				Padding(0, 1)
+
+	errorHintStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("244")).
+			Italic(true)
+)
+
+// schemaLoadCmd returns the command that (re)loads schemas for this session
+func (m Model) schemaLoadCmd() tea.Cmd {
+	if m.loadCmd != nil {
+		return m.loadCmd
+	}
+	return loadSchemaCmd(m.workingDir(), m.toolInfo)
+}
+
+// canRunInit reports whether schemas come from a working directory that init can repair
+func (m Model) canRunInit() bool {
+	return m.loadCmd == nil
+}
+
+// otherTool returns the tool offered by the switch-tool action
+func (m Model) otherTool() string {
+	if m.toolInfo.Tool == "tofu" {
+		return "terraform"
+	}
+	return "tofu"
+}
+
+// showLoadError switches to the error stage for a failed load or init
+func (m *Model) showLoadError(err error) {
+	m.loadErr = err
+	m.errorNotice = ""
+	m.stage = StageError
+}
+
+// retryLoad returns to the loading stage and reloads schemas
+func (m *Model) retryLoad() tea.Cmd {
+	m.loadErr = nil
+	m.errorNotice = ""
+	m.stage = StageLoading
+	return m.schemaLoadCmd()
+}
+
+// handleErrorKey handles the retry, init and switch-tool actions of the error stage
+func (m *Model) handleErrorKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "r":
+		return m.retryLoad(), true
+	case "w":
+		if !m.hasWorkspaces() {
+			return nil, false
+		}
+		m.showWorkspacePicker()
+		return nil, true
+	case "i":
+		if !m.canRunInit() {
+			return nil, true
+		}
+		return m.startInit(), true
+	case "t":
+		tool := m.otherTool()
+		tfInfo, err := terraform.ResolveTerraformInfo(tool, "")
+		if err != nil {
+			m.errorNotice = fmt.Sprintf("Cannot switch to %s: %v", tool, err)
+			return nil, true
+		}
+		tfInfo.Installation = m.toolInfo.Installation
+		m.toolInfo = tfInfo
+		m.version = ""
+		m.types.SetToolInfo(tfInfo, "")
+		m.status.SetToolInfo(tfInfo, "")
+		return m.retryLoad(), true
+	}
+	return nil, false
+}
+
+// loadErrorSuggestions derives likely fixes from a load failure
+func loadErrorSuggestions(err error, tfInfo terraform.TerraformInfo, canInit bool) []string {
+	var suggestions []string
+
+	var cmdErr *terraform.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.BinaryNotFound() {
+		return []string{
+			fmt.Sprintf("%s was not found. Install it or pass --binary with its path.", cmdErr.Binary),
+			"Press t to try the other tool.",
+		}
+	}
+
+	var missingErr *terraform.MissingMirrorPackageError
+	if errors.As(err, &missingErr) {
+		return []string{
+			fmt.Sprintf("Add the package to the mirror, e.g. with %s providers mirror %s on a machine with registry access.", tfInfo.Tool, missingErr.MirrorDir),
+			"Press r to retry once the problem is fixed.",
+		}
+	}
+
+	output := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(output, "no configuration files"):
+		suggestions = append(suggestions, "The directory has no Terraform configuration; start the explorer in a module directory.")
+	case strings.Contains(output, `run "terraform init"`),
+		strings.Contains(output, `run "tofu init"`),
+		strings.Contains(output, "please run"),
+		strings.Contains(output, "inconsistent dependency lock file"),
+		strings.Contains(output, "required plugins are not installed"),
+		strings.Contains(output, "missing required provider"),
+		strings.Contains(output, "could not load plugin"):
+		if canInit {
+			suggestions = append(suggestions, fmt.Sprintf("Providers are missing or out of date. Press i to run %s init.", tfInfo.Tool))
+		} else {
+			suggestions = append(suggestions, fmt.Sprintf("Providers are missing or out of date. Run %s init in the workspace.", tfInfo.Tool))
+		}
+	case strings.Contains(output, "401"), strings.Contains(output, "403"), strings.Contains(output, "credentials"):
+		suggestions = append(suggestions, "The registry rejected the request; check your registry credentials.")
+	}
+
+	return append(suggestions, "Press r to retry once the problem is fixed.")
+}
+
+// renderErrorView renders the failed command, its output and the available actions
+func (m Model) renderErrorView() string {
+	var cmdErr *terraform.CommandError
+	isCmdErr := errors.As(m.loadErr, &cmdErr)
+
+	title := "Failed to load provider schemas"
+	switch {
+	case errors.Is(m.loadErr, context.Canceled):
+		title = "Init cancelled"
+	case isCmdErr && len(cmdErr.Args) > 0 && cmdErr.Args[0] == "init":
+		title = fmt.Sprintf("%s init failed", m.toolInfo.Tool)
+	}
+
+	var sections []string
+	sections = append(sections, errorTitleStyle.Render(title), "")
+
+	output := m.loadErr.Error()
+	if isCmdErr {
+		dir := cmdErr.Dir
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		sections = append(sections,
+			errorLabelStyle.Render("Command: ")+cmdErr.Command(),
+			errorLabelStyle.Render("Directory: ")+dir,
+			errorLabelStyle.Render("Error: ")+cmdErr.Err.Error(),
+		)
+		output = strings.TrimSpace(cmdErr.Stderr)
+	}
+
+	if output != "" {
+		// Keep the tail of the output, which is where terraform prints the actual error
+		maxLines := m.height - 16
+		if maxLines < 3 {
+			maxLines = 3
+		}
+		lines := strings.Split(output, "\n")
+		if len(lines) > maxLines {
+			lines = append([]string{"…"}, lines[len(lines)-maxLines:]...)
+		}
+		width := m.width - 4
+		if width < 20 {
+			width = 20
+		}
+		sections = append(sections, errorOutputStyle.Width(width).Render(strings.Join(lines, "\n")))
+	}
+
+	sections = append(sections, "", errorLabelStyle.Render("Suggestions:"))
+	for _, s := range loadErrorSuggestions(m.loadErr, m.toolInfo, m.canRunInit()) {
+		sections = append(sections, "  • "+s)
+	}
+
+	if m.errorNotice != "" {
+		sections = append(sections, "", errorTitleStyle.Render(m.errorNotice))
+	}
+
+	actions := []string{"r retry"}
+	if m.canRunInit() {
+		actions = append(actions, "i run init")
+	}
+	actions = append(actions, "t switch to "+m.otherTool())
+	if m.hasWorkspaces() {
+		actions = append(actions, "w workspaces")
+	}
+	actions = append(actions, "q quit")
+	sections = append(sections, "", errorHintStyle.Render(strings.Join(actions, " • ")))
+
+	return lipgloss.JoinVertical(lipgloss.Top, lipgloss.JoinVertical(lipgloss.Left, sections...), m.status.Render())
+}
@@ -6,6 +6,7 @@ import (
 
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/terraconstructs/provider-explorer/internal/schema"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // ResourceSection represents which section of a resource (Arguments or Attributes)
@@ -38,32 +39,17 @@ func ConvertArgumentsToHCLVariables(resourceSchema *schema.Schema) string {
 	for name, attr := range resourceSchema.Block.Attributes {
 		if attr.Required || attr.Optional {
 			hasArguments = true
+			writeVariable(&b, name, schema.AttributeType(attr), attr.Description, "argument", attr.Required, "")
+		}
+	}
 
-			// Generate variable block
-			b.WriteString(fmt.Sprintf("variable \"%s\" {\n", name))
-
-			// Add type
-			hclType := convertTypeToHCLType(attr.AttributeType)
-			b.WriteString(fmt.Sprintf("  type = %s\n", hclType))
-
-			// Add description
-			description := attr.Description
-			if description == "" {
-				if attr.Required {
-					description = fmt.Sprintf("Required argument for %s", name)
-				} else {
-					description = fmt.Sprintf("Optional argument for %s", name)
-				}
-			}
-			b.WriteString(fmt.Sprintf("  description = \"%s\"\n", escapeDescription(description)))
-
-			// Add default value for optional arguments
-			if !attr.Required {
-				b.WriteString("  default = null\n")
-			}
-
-			b.WriteString("}\n\n")
+	for _, name := range sortedBlockKeys(resourceSchema.Block.NestedBlocks) {
+		nb := resourceSchema.Block.NestedBlocks[name]
+		if nb == nil || nb.Block == nil {
+			continue
 		}
+		hasArguments = true
+		writeVariable(&b, name, schema.BlockType(nb), nb.Block.Description, "block", nb.MinItems > 0, "")
 	}
 
 	if !hasArguments {
@@ -85,9 +71,9 @@ func ConvertAttributesToHCLOutputs(resourceName string, resourceSchema *schema.S
 			hasAttributes = true
 
 			// Add nested schema as comment if it's a complex type
-			if isComplexType(attr.AttributeType) {
+			if schema.IsStructuralType(schema.AttributeType(attr)) {
 				b.WriteString(fmt.Sprintf("# %s structure:\n", name))
-				b.WriteString(fmt.Sprintf("# %s\n", renderAttributeTypeComment(attr.AttributeType)))
+				b.WriteString(fmt.Sprintf("# %s\n", schema.HCLType(schema.AttributeType(attr))))
 			}
 
 			// Generate output block
@@ -118,9 +104,17 @@ func ConvertAttributesToHCLOutputs(resourceName string, resourceSchema *schema.S
 	return b.String()
 }
 
-// ConvertSelectedArgumentsToHCLVariables converts only selected argument attributes into variables.
+// ConvertSelectedArgumentsToHCLVariables converts only selected arguments into variables.
 // It respects hierarchy: a nested attribute is included only if all parent blocks are selected.
+// Nested blocks become a single variable holding the selected arguments below them.
 func ConvertSelectedArgumentsToHCLVariables(resourceSchema *schema.Schema, selectedPaths [][]string) string {
+	return convertSelectedArgumentsToHCLVariables(resourceSchema, selectedPaths, nil)
+}
+
+// convertSelectedArgumentsToHCLVariables is ConvertSelectedArgumentsToHCLVariables with
+// the defaults of optional arguments as HCL expressions by name, e.g. for the variables
+// of a module. Optional arguments without one default to null.
+func convertSelectedArgumentsToHCLVariables(resourceSchema *schema.Schema, selectedPaths [][]string, defaults map[string]string) string {
 	if resourceSchema == nil || resourceSchema.Block == nil {
 		return "# No arguments available for variable conversion\n"
 	}
@@ -148,28 +142,24 @@ func ConvertSelectedArgumentsToHCLVariables(resourceSchema *schema.Schema, selec
 			continue
 		}
 
-		// Resolve path to attribute
+		// Nested blocks and nested attributes are exported as a whole, covering
+		// everything below them
+		if len(path) > 1 {
+			continue
+		}
+
+		name := path[0]
 		if attr, found := resolveAttributeByPath(resourceSchema.Block, path); found {
 			// Only include arguments (required/optional, not computed)
 			if attr.Required || attr.Optional {
-				varName := strings.Join(path, "_")
-				b.WriteString(fmt.Sprintf("variable \"%s\" {\n", varName))
-				b.WriteString(fmt.Sprintf("  type = %s\n", convertTypeToHCLType(attr.AttributeType)))
-				description := attr.Description
-				if description == "" {
-					if attr.Required {
-						description = fmt.Sprintf("Required argument for %s", varName)
-					} else {
-						description = fmt.Sprintf("Optional argument for %s", varName)
-					}
-				}
-				b.WriteString(fmt.Sprintf("  description = \"%s\"\n", escapeDescription(description)))
-				if !attr.Required {
-					b.WriteString("  default = null\n")
-				}
-				b.WriteString("}\n\n")
+				writeVariable(&b, name, selectedArgumentType(attr, path, selSet), attr.Description, "argument", attr.Required, defaults[name])
 				included++
 			}
+			continue
+		}
+		if nb, ok := resourceSchema.Block.NestedBlocks[name]; ok && nb != nil && nb.Block != nil {
+			writeVariable(&b, name, selectedBlockType(nb, path, selSet), nb.Block.Description, "block", nb.MinItems > 0, "")
+			included++
 		}
 	}
 
@@ -210,20 +200,19 @@ func ConvertSelectedAttributesToHCLOutputs(resourceName string, resourceSchema *
 			continue
 		}
 
-		if attr, found := resolveAttributeByPath(resourceSchema.Block, path); found {
+		// A nested attribute whose nested attributes are selected is exported through them
+		if attr, found := resolveAttributeByPath(resourceSchema.Block, path); found && !hasSelectedDescendant(path, selSet) {
 			if attr.Computed {
 				// Name outputs by joining path with underscores for uniqueness
 				outName := strings.Join(path, "_")
 
 				// Add nested schema as comment if complex type
-				if isComplexType(attr.AttributeType) {
+				if schema.IsStructuralType(schema.AttributeType(attr)) {
 					b.WriteString(fmt.Sprintf("# %s structure:\n", outName))
-					b.WriteString(fmt.Sprintf("# %s\n", renderAttributeTypeComment(attr.AttributeType)))
+					b.WriteString(fmt.Sprintf("# %s\n", schema.HCLType(schema.AttributeType(attr))))
 				}
 
-				// Reference uses dot-joined attribute path
-				refPath := strings.Join(path, ".")
-				resourceRef := fmt.Sprintf("%s.%s.%s", resourceName, instanceName, refPath)
+				resourceRef := attributeReference(resourceName+"."+instanceName, resourceSchema.Block, path)
 
 				b.WriteString(fmt.Sprintf("output \"%s\" {\n", outName))
 				b.WriteString(fmt.Sprintf("  value = %s\n", resourceRef))
@@ -246,91 +235,289 @@ func ConvertSelectedAttributesToHCLOutputs(resourceName string, resourceSchema *
 	return b.String()
 }
 
-// resolveAttributeByPath traverses a schema block hierarchy to find an attribute at the given path.
+// resolveAttributeByPath traverses a schema block hierarchy, through nested blocks and
+// nested attributes, to find an attribute at the given path.
 func resolveAttributeByPath(block *tfjson.SchemaBlock, path []string) (*tfjson.SchemaAttribute, bool) {
 	if block == nil {
 		return nil, false
 	}
-	if len(path) == 0 {
-		return nil, false
-	}
-	// Walk through nested blocks until last element
-	cur := block
-	for i := 0; i < len(path)-1; i++ {
-		nb, ok := cur.NestedBlocks[path[i]]
-		if !ok || nb == nil || nb.Block == nil {
+	attrs, blocks := block.Attributes, block.NestedBlocks
+	for i, name := range path {
+		last := i == len(path)-1
+		if attr, ok := attrs[name]; ok && attr != nil {
+			if last {
+				return attr, true
+			}
+			if attr.AttributeNestedType == nil {
+				return nil, false
+			}
+			attrs, blocks = attr.AttributeNestedType.Attributes, nil
+			continue
+		}
+		nb, ok := blocks[name]
+		if last || !ok || nb == nil || nb.Block == nil {
 			return nil, false
 		}
-		cur = nb.Block
+		attrs, blocks = nb.Block.Attributes, nb.Block.NestedBlocks
 	}
-	// Last element should resolve to an attribute
-	last := path[len(path)-1]
-	attr, ok := cur.Attributes[last]
-	if !ok || attr == nil {
+	return nil, false
+}
+
+// resolveNestedAttributeByPath finds the attribute at the given path if it nests attributes
+func resolveNestedAttributeByPath(block *tfjson.SchemaBlock, path []string) (*tfjson.SchemaNestedAttributeType, bool) {
+	attr, ok := resolveAttributeByPath(block, path)
+	if !ok || attr.AttributeNestedType == nil {
 		return nil, false
 	}
-	return attr, true
+	return attr.AttributeNestedType, true
 }
 
-// convertTypeToHCLType converts Terraform schema types to HCL variable types
-func convertTypeToHCLType(attrType interface{}) string {
-	if attrType == nil {
-		return "any"
+// hasSelectedDescendant reports whether any path below the given one is selected
+func hasSelectedDescendant(path []string, selected map[string]struct{}) bool {
+	prefix := strings.Join(path, ".") + "."
+	for key := range selected {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
 	}
-	// For cty.Type, we'll use a simplified string representation
-	// This will need to be enhanced later for better type mapping
-	typeStr := fmt.Sprintf("%v", attrType)
+	return false
+}
 
-	// Basic type mapping for common cases
-	if strings.Contains(typeStr, "string") {
-		return "string"
+// selectedArgumentType returns the variable type of an argument. For a nested attribute
+// it is an object of the selected nested arguments only, so nested attributes that were
+// deselected in the tree, and computed ones, are left out of the variable.
+func selectedArgumentType(attr *tfjson.SchemaAttribute, path []string, selected map[string]struct{}) cty.Type {
+	nested := attr.AttributeNestedType
+	if nested == nil {
+		return attr.AttributeType
+	}
+	attrs := make(map[string]cty.Type)
+	var optional []string
+	for name, child := range nested.Attributes {
+		childPath := append(append([]string(nil), path...), name)
+		if _, ok := selected[strings.Join(childPath, ".")]; !ok || !(child.Required || child.Optional) {
+			continue
+		}
+		attrs[name] = selectedArgumentType(child, childPath, selected)
+		if !child.Required {
+			optional = append(optional, name)
+		}
 	}
-	if strings.Contains(typeStr, "number") {
-		return "number"
+	return schema.WrapNesting(cty.ObjectWithOptionalAttrs(attrs, optional), nested.NestingMode)
+}
+
+// selectedBlockType returns the variable type of a nested block: an object of the
+// selected arguments and nested blocks below it. Blocks limited to one item hold a
+// single object rather than a list or set of one.
+func selectedBlockType(nb *tfjson.SchemaBlockType, path []string, selected map[string]struct{}) cty.Type {
+	attrs := make(map[string]cty.Type)
+	var optional []string
+	for name, attr := range nb.Block.Attributes {
+		childPath := append(append([]string(nil), path...), name)
+		if _, ok := selected[strings.Join(childPath, ".")]; !ok || !(attr.Required || attr.Optional) {
+			continue
+		}
+		attrs[name] = selectedArgumentType(attr, childPath, selected)
+		if !attr.Required {
+			optional = append(optional, name)
+		}
 	}
-	if strings.Contains(typeStr, "bool") {
-		return "bool"
+	for name, child := range nb.Block.NestedBlocks {
+		childPath := append(append([]string(nil), path...), name)
+		if _, ok := selected[strings.Join(childPath, ".")]; !ok || child == nil || child.Block == nil {
+			continue
+		}
+		attrs[name] = selectedBlockType(child, childPath, selected)
+		if child.MinItems == 0 {
+			optional = append(optional, name)
+		}
 	}
-	if strings.Contains(typeStr, "list") {
-		return "list(any)"
+	return schema.WrapNesting(cty.ObjectWithOptionalAttrs(attrs, optional), schema.BlockNesting(nb))
+}
+
+// writeVariable writes a variable block for an argument or nested block. Variables of
+// optional ones default to defaultExpr, or null without one.
+func writeVariable(b *strings.Builder, name string, ty cty.Type, description, kind string, required bool, defaultExpr string) {
+	b.WriteString(fmt.Sprintf("variable \"%s\" {\n", name))
+	b.WriteString(fmt.Sprintf("  type = %s\n", schema.HCLType(ty)))
+	if description == "" {
+		if required {
+			description = fmt.Sprintf("Required %s for %s", kind, name)
+		} else {
+			description = fmt.Sprintf("Optional %s for %s", kind, name)
+		}
 	}
-	if strings.Contains(typeStr, "map") {
-		return "map(any)"
+	b.WriteString(fmt.Sprintf("  description = \"%s\"\n", escapeDescription(description)))
+	if !required {
+		if defaultExpr == "" {
+			defaultExpr = "null"
+		}
+		b.WriteString(fmt.Sprintf("  default = %s\n", strings.ReplaceAll(defaultExpr, "\n", "\n  ")))
 	}
-	if strings.Contains(typeStr, "set") {
-		return "set(any)"
+	b.WriteString("}\n\n")
+}
+
+// attributeReference returns the expression referencing the attribute at path below
+// base. Nested attributes and blocks in list and set mode are traversed with splats and
+// those in map mode through their values, flattening once the path crosses several of
+// them. Blocks limited to one item are unwrapped with one().
+func attributeReference(base string, block *tfjson.SchemaBlock, path []string) string {
+	expr := base
+	collection := false               // expr evaluates to a list of the objects traversed
+	var mode tfjson.SchemaNestingMode // nesting mode of the attribute expr refers to
+	attrs, blocks := block.Attributes, block.NestedBlocks
+	for _, name := range path {
+		switch mode {
+		case tfjson.SchemaNestingModeList, tfjson.SchemaNestingModeSet:
+			if collection {
+				expr = "flatten(" + expr + ")"
+			}
+			collection = true
+		case tfjson.SchemaNestingModeMap:
+			if collection {
+				expr = "flatten([for m in " + expr + " : values(m)])"
+			} else {
+				expr = "values(" + expr + ")"
+			}
+			collection = true
+		}
+		if collection {
+			expr += "[*]." + name
+		} else {
+			expr += "." + name
+		}
+
+		mode = ""
+		if attr, ok := attrs[name]; ok && attr != nil {
+			if attr.AttributeNestedType != nil {
+				mode = attr.AttributeNestedType.NestingMode
+				attrs, blocks = attr.AttributeNestedType.Attributes, nil
+			}
+			continue
+		}
+		if nb, ok := blocks[name]; ok && nb != nil && nb.Block != nil {
+			mode = nb.NestingMode
+			if schema.BlockNesting(nb) == tfjson.SchemaNestingModeSingle && !collection {
+				mode = tfjson.SchemaNestingModeSingle
+				if nb.NestingMode == tfjson.SchemaNestingModeList || nb.NestingMode == tfjson.SchemaNestingModeSet {
+					expr = "one(" + expr + ")"
+				}
+			}
+			attrs, blocks = nb.Block.Attributes, nb.Block.NestedBlocks
+		}
 	}
+	return expr
+}
 
-	return "any"
+// resolveBlockByPath traverses nested blocks to find the block at the given path.
+func resolveBlockByPath(block *tfjson.SchemaBlock, path []string) (*tfjson.SchemaBlock, bool) {
+	if block == nil || len(path) == 0 {
+		return nil, false
+	}
+	cur := block
+	for _, name := range path {
+		nb, ok := cur.NestedBlocks[name]
+		if !ok || nb == nil || nb.Block == nil {
+			return nil, false
+		}
+		cur = nb.Block
+	}
+	return cur, true
 }
 
-// convertObjectTypeToHCL converts object type definitions to HCL object syntax
-func convertObjectTypeToHCL(objMap map[string]interface{}) string {
-	if len(objMap) == 0 {
-		return "object({})"
+// ResolveSelectedPaths converts dotted path selectors (e.g. "root_block_device.volume_size")
+// into the selection the schema tree would hold for them. Ancestor blocks of a nested path are
+// selected implicitly so the hierarchical selection rule is satisfied, and selecting a block
+// selects all of its descendants, as toggling a block in the tree does.
+// Attribute selectors must match the mode: arguments need required/optional attributes and
+// attributes need computed ones.
+func ResolveSelectedPaths(block *tfjson.SchemaBlock, selectors []string, mode ViewMode) ([][]string, error) {
+	if block == nil {
+		return nil, fmt.Errorf("schema has no block")
 	}
 
-	var fields []string
-	for key, value := range objMap {
-		fieldType := convertTypeToHCLType(value)
-		fields = append(fields, fmt.Sprintf("%s = %s", key, fieldType))
+	seen := make(map[string]struct{})
+	var out [][]string
+	add := func(path []string) {
+		key := strings.Join(path, ".")
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		out = append(out, append([]string(nil), path...))
 	}
 
-	return fmt.Sprintf("object({\n    %s\n  })", strings.Join(fields, ",\n    "))
-}
+	for _, selector := range selectors {
+		selector = strings.TrimSpace(selector)
+		if selector == "" {
+			continue
+		}
+		path := strings.Split(selector, ".")
 
-// convertTupleTypeToHCL converts tuple type definitions to HCL tuple syntax
-func convertTupleTypeToHCL(tupleTypes []interface{}) string {
-	if len(tupleTypes) == 0 {
-		return "tuple([])"
+		// Implicitly select ancestor blocks and nested attributes
+		for i := 1; i < len(path); i++ {
+			_, isBlock := resolveBlockByPath(block, path[:i])
+			_, isNested := resolveNestedAttributeByPath(block, path[:i])
+			if !isBlock && !isNested {
+				return nil, fmt.Errorf("unknown path %q: %q is not a nested block or attribute", selector, strings.Join(path[:i], "."))
+			}
+			add(path[:i])
+		}
+
+		if attr, ok := resolveAttributeByPath(block, path); ok {
+			switch mode {
+			case ArgumentsMode:
+				if !attr.Required && !attr.Optional {
+					return nil, fmt.Errorf("path %q is a computed attribute, not an argument", selector)
+				}
+			case AttributesMode:
+				if !attr.Computed {
+					return nil, fmt.Errorf("path %q is not a computed attribute", selector)
+				}
+			}
+			add(path)
+			addAttributeDescendants(attr, path, add)
+			continue
+		}
+
+		nested, ok := resolveBlockByPath(block, path)
+		if !ok {
+			return nil, fmt.Errorf("unknown path %q", selector)
+		}
+		add(path)
+		addBlockDescendants(nested, path, add)
 	}
 
-	var types []string
-	for _, t := range tupleTypes {
-		types = append(types, convertTypeToHCLType(t))
+	return out, nil
+}
+
+// addBlockDescendants adds every attribute and nested block below a block, in tree order.
+func addBlockDescendants(block *tfjson.SchemaBlock, path []string, add func([]string)) {
+	for _, name := range sortedAttrKeys(block.Attributes) {
+		childPath := append(append([]string(nil), path...), name)
+		add(childPath)
+		addAttributeDescendants(block.Attributes[name], childPath, add)
+	}
+	for _, name := range sortedBlockKeys(block.NestedBlocks) {
+		nb := block.NestedBlocks[name]
+		childPath := append(append([]string(nil), path...), name)
+		add(childPath)
+		if nb != nil && nb.Block != nil {
+			addBlockDescendants(nb.Block, childPath, add)
+		}
 	}
+}
 
-	return fmt.Sprintf("tuple([%s])", strings.Join(types, ", "))
+// addAttributeDescendants adds the attributes nested below an attribute, in tree order.
+func addAttributeDescendants(attr *tfjson.SchemaAttribute, path []string, add func([]string)) {
+	if attr == nil || attr.AttributeNestedType == nil {
+		return
+	}
+	for _, name := range sortedAttrKeys(attr.AttributeNestedType.Attributes) {
+		childPath := append(append([]string(nil), path...), name)
+		add(childPath)
+		addAttributeDescendants(attr.AttributeNestedType.Attributes[name], childPath, add)
+	}
 }
 
 // generateResourceReference creates a terraform resource reference
@@ -363,25 +550,6 @@ func generateInstanceName(resourceName string) string {
 	return name
 }
 
-// isComplexType checks if an attribute type is complex (nested)
-func isComplexType(attrType interface{}) bool {
-	if attrType == nil {
-		return false
-	}
-	// For cty.Type, check if it's a complex type
-	typeStr := fmt.Sprintf("%v", attrType)
-	return strings.Contains(typeStr, "object") || strings.Contains(typeStr, "tuple")
-}
-
-// renderAttributeTypeComment renders type information for comments
-func renderAttributeTypeComment(attrType interface{}) string {
-	if attrType == nil {
-		return "unknown"
-	}
-	// For cty.Type, use its string representation
-	return fmt.Sprintf("%v", attrType)
-}
-
 // escapeDescription escapes quotes and special characters in descriptions
 func escapeDescription(desc string) string {
 	// Replace quotes and newlines
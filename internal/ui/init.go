@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+var (
+	initDialogStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("14")).
+			Padding(1, 2)
+
+	initTitleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212"))
+
+	initOutputStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1)
+)
+
+// initStartMsg starts init without asking, as requested by --yes
+type initStartMsg struct{}
+
+// initOutputMsg carries one line of init output
+type initOutputMsg struct {
+	line string
+}
+
+// initFinishedMsg is sent when init exits
+type initFinishedMsg struct {
+	err error
+}
+
+// SetInitRequired makes the session initialize the working directory before loading
+// schemas. Without autoConfirm a confirmation dialog is shown first.
+func (m *Model) SetInitRequired(autoConfirm bool) {
+	if autoConfirm {
+		m.initAutoStart = true
+		return
+	}
+	m.stage = StageInitConfirm
+}
+
+// startInit launches init and returns the command that streams its output
+func (m *Model) startInit() tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	proc, err := terraform.StartInit(ctx, m.workingDir(), m.toolInfo)
+	if err != nil {
+		cancel()
+		m.showLoadError(err)
+		return nil
+	}
+
+	m.initProc = proc
+	m.initCancel = cancel
+	m.initLines = nil
+	m.loadErr = nil
+	m.stage = StageInitRunning
+	return waitForInitOutput(proc)
+}
+
+// waitForInitOutput delivers the next output line, or the result once the process exits
+func waitForInitOutput(proc *terraform.InitProcess) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-proc.Lines()
+		if !ok {
+			return initFinishedMsg{err: proc.Wait()}
+		}
+		return initOutputMsg{line: line}
+	}
+}
+
+// finishInit releases the state of a completed init
+func (m *Model) finishInit() {
+	if m.initCancel != nil {
+		m.initCancel()
+	}
+	m.initProc = nil
+	m.initCancel = nil
+}
+
+// cancelInit stops a running init; its initFinishedMsg reports the cancellation
+func (m *Model) cancelInit() {
+	if m.initCancel != nil {
+		m.initCancel()
+	}
+}
+
+// handleInitKey handles the confirmation dialog and cancellation of a running init
+func (m *Model) handleInitKey(msg tea.KeyMsg) tea.Cmd {
+	switch m.stage {
+	case StageInitConfirm:
+		switch msg.String() {
+		case "y", "enter":
+			return m.startInit()
+		case "n", "esc":
+			// Try the workspace as it is; the error stage offers init again if loading fails
+			return m.retryLoad()
+		case "ctrl+c", "q":
+			return tea.Quit
+		}
+	case StageInitRunning:
+		switch msg.String() {
+		case "esc":
+			m.cancelInit()
+		case "ctrl+c":
+			m.cancelInit()
+			return tea.Quit
+		}
+	}
+	return nil
+}
+
+// workingDirLabel returns the absolute working directory for display
+func (m Model) workingDirLabel() string {
+	dir := m.workingDir()
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+// renderInitConfirmView renders the init confirmation dialog
+func (m Model) renderInitConfirmView() string {
+	tool := m.toolInfo.Tool
+	if tool == "" {
+		tool = "terraform"
+	}
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		initTitleStyle.Render("Initialize working directory?"),
+		"",
+		fmt.Sprintf("Terraform configuration detected in %s", m.workingDirLabel()),
+		fmt.Sprintf("This will run '%s init' to download providers.", tool),
+		"",
+		errorHintStyle.Render("y/enter run init • n skip • q quit"),
+	)
+	return lipgloss.JoinVertical(lipgloss.Top, initDialogStyle.Render(body), m.status.Render())
+}
+
+// renderInitRunningView renders the streamed output of a running init
+func (m Model) renderInitRunningView() string {
+	title := initTitleStyle.Render(fmt.Sprintf("Running %s init in %s…", m.toolInfo.Tool, m.workingDirLabel()))
+
+	// Show the most recent lines that fit on screen
+	maxLines := m.height - 6
+	if maxLines < 3 {
+		maxLines = 3
+	}
+	lines := m.initLines
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	width := m.width - 4
+	if width < 20 {
+		width = 20
+	}
+	output := initOutputStyle.Width(width).Render(strings.Join(lines, "\n"))
+
+	return lipgloss.JoinVertical(lipgloss.Top, title, output, errorHintStyle.Render("esc cancel • ctrl+c cancel and quit"), m.status.Render())
+}
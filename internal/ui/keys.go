@@ -10,6 +10,11 @@ type KeyMap struct {
 	Left  key.Binding
 	Right key.Binding
 
+	// Folding
+	ExpandAll     key.Binding
+	CollapseAll   key.Binding
+	ExpandToDepth key.Binding
+
 	// Focus
 	Tab    key.Binding
 	Enter  key.Binding
@@ -22,6 +27,10 @@ type KeyMap struct {
 
 	// Toggle modes
 	ToggleArgsAttrs key.Binding
+	ToggleDetails   key.Binding
+
+	// Tree node details
+	CopyReference key.Binding
 
 	// Quit
 	Quit key.Binding
@@ -49,6 +58,18 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("right", "l"),
 			key.WithHelp("→/l", "expand/forward"),
 		),
+		ExpandAll: key.NewBinding(
+			key.WithKeys("+"),
+			key.WithHelp("+", "expand all"),
+		),
+		CollapseAll: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "collapse all"),
+		),
+		ExpandToDepth: key.NewBinding(
+			key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+			key.WithHelp("1-9", "expand to depth"),
+		),
 		Tab: key.NewBinding(
 			key.WithKeys("tab"),
 			key.WithHelp("tab", "toggle focus"),
@@ -77,6 +98,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("a"),
 			key.WithHelp("a", "toggle args/attrs"),
 		),
+		ToggleDetails: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "toggle details"),
+		),
+		CopyReference: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy reference"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
 			key.WithHelp("q", "quit"),
@@ -96,9 +125,10 @@ func (k KeyMap) ShortHelp() []key.Binding {
 // FullHelp returns keybindings for the expanded help view
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right},     // navigation
-		{k.Tab, k.Enter, k.Escape},          // focus
-		{k.Space, k.Export, k.Copy},         // actions
-		{k.ToggleArgsAttrs, k.Help, k.Quit}, // misc
+		{k.Up, k.Down, k.Left, k.Right},                      // navigation
+		{k.ExpandAll, k.CollapseAll, k.ExpandToDepth},        // folding
+		{k.Tab, k.Enter, k.Escape},                           // focus
+		{k.Space, k.Export, k.Copy, k.CopyReference},         // actions
+		{k.ToggleArgsAttrs, k.ToggleDetails, k.Help, k.Quit}, // misc
 	}
 }
@@ -5,13 +5,18 @@ import (
 	"io"
 	"os"
 
+	tea "github.com/charmbracelet/bubbletea"
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/terraconstructs/provider-explorer/internal/terraform"
 )
 
-// LoadProvidersSchemaFromFile loads provider schemas from a JSON file
-// This is used by tests to bypass the CLI and load fixtures
+// LoadProvidersSchemaFromFile loads provider schemas from a JSON file.
+// This is used by --schema-file and by tests to bypass the CLI and load fixtures.
+// A path of "-" reads the document from stdin.
 func LoadProvidersSchemaFromFile(path string) (*tfjson.ProviderSchemas, error) {
+	if path == "-" {
+		return LoadProvidersSchemaFromReader(os.Stdin)
+	}
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -31,6 +36,36 @@ func LoadProvidersSchemaFromReader(r io.Reader) (*tfjson.ProviderSchemas, error)
 	return &ps, nil
 }
 
+// NewModelFromSchemas creates a model that opens the given schemas instead of fetching them
+// from a Terraform working directory. Unlike NewModelWithSchemas, it goes through the regular
+// load flow so single-provider auto-selection and feature gating behave as in a normal session.
+func NewModelFromSchemas(schemas *tfjson.ProviderSchemas, toolInfo terraform.TerraformInfo, version string, width, height int) Model {
+	m := NewModel(width, height)
+	m.loadCmd = func() tea.Msg {
+		return schemaLoadedMsg{
+			schemas:  schemas,
+			toolInfo: toolInfo,
+			version:  version,
+		}
+	}
+	return m
+}
+
+// NewModelFromLoader creates a model that loads schemas with load instead of running
+// `providers schema -json`, e.g. straight from provider plugins. Load errors are shown on
+// the error screen, where the load can be retried.
+func NewModelFromLoader(load func() (*terraform.SchemaWithVersionInfo, error), width, height int) Model {
+	m := NewModel(width, height)
+	m.loadCmd = func() tea.Msg {
+		schemaWithVersion, err := load()
+		if err != nil {
+			return schemaLoadedMsg{err: err}
+		}
+		return newSchemaLoadedMsg(schemaWithVersion)
+	}
+	return m
+}
+
 // NewModelWithSchemas creates a model pre-loaded with schemas (for testing)
 func NewModelWithSchemas(schemas *tfjson.ProviderSchemas, width, height int) Model {
 	m := NewModel(width, height)
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	markdownHeadingStyle = lipgloss.NewStyle().Bold(true)
+	markdownBoldStyle    = lipgloss.NewStyle().Bold(true)
+	markdownItalicStyle  = lipgloss.NewStyle().Italic(true)
+	markdownCodeStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("180"))
+	markdownLinkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("75"))
+
+	markdownHeading  = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+	markdownBullet   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownAutoLink = regexp.MustCompile(`<(https?://[^>]+)>`)
+	markdownBold     = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownItalic   = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+)
+
+// renderMarkdown renders a markdown description for the terminal. Provider schemas only
+// use a small part of markdown in descriptions — paragraphs, headings, lists, code,
+// emphasis and links — so that is all it handles; anything else is shown as written.
+// Lines are wrapped to width.
+func renderMarkdown(text string, width int) string {
+	var lines []string
+	fenced := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			lines = append(lines, markdownCodeStyle.Render("  "+line))
+			continue
+		}
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			lines = append(lines, markdownHeadingStyle.Render(m[1]))
+			continue
+		}
+		if m := markdownBullet.FindStringSubmatch(line); m != nil {
+			line = m[1] + "• " + m[2]
+		}
+		lines = append(lines, renderMarkdownInline(line))
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
+
+// renderMarkdownInline styles code spans, emphasis and links within a line. Code spans
+// are left as written, so markup inside them is not interpreted.
+func renderMarkdownInline(line string) string {
+	parts := strings.Split(line, "`")
+	if len(parts)%2 == 0 {
+		// An unmatched backtick is not a code span
+		parts[len(parts)-2] += "`" + parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			b.WriteString(markdownCodeStyle.Render(part))
+			continue
+		}
+		part = markdownLink.ReplaceAllStringFunc(part, func(s string) string {
+			m := markdownLink.FindStringSubmatch(s)
+			return m[1] + " (" + markdownLinkStyle.Render(m[2]) + ")"
+		})
+		part = markdownAutoLink.ReplaceAllStringFunc(part, func(s string) string {
+			return markdownLinkStyle.Render(markdownAutoLink.FindStringSubmatch(s)[1])
+		})
+		part = markdownBold.ReplaceAllStringFunc(part, func(s string) string {
+			m := markdownBold.FindStringSubmatch(s)
+			return markdownBoldStyle.Render(m[1] + m[2])
+		})
+		part = markdownItalic.ReplaceAllStringFunc(part, func(s string) string {
+			return markdownItalicStyle.Render(markdownItalic.FindStringSubmatch(s)[1])
+		})
+		b.WriteString(part)
+	}
+	return b.String()
+}
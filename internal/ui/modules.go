@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/terraconstructs/provider-explorer/internal/config"
+)
+
+// maxDefaultLength bounds the variable defaults shown next to tree labels
+const maxDefaultLength = 40
+
+// moduleEntity is the Modules category counterpart of a provider schema. The module's
+// variables make up the entity schema, shown and exported as arguments, and its outputs
+// a separate schema of computed attributes, shown and exported as attributes. They are
+// kept apart because a module may well have a variable and an output of the same name.
+type moduleEntity struct {
+	module   config.Module
+	outputs  *tfjson.Schema
+	defaults map[string]interface{} // defaults of the optional variables by variable name
+}
+
+// setModules offers the modules of the open working directory in the Modules category
+func (m *Model) setModules(modules []config.Module) {
+	m.modules = modules
+	m.types.SetModuleCount(len(modules))
+	m.entities.SetModules(modules)
+}
+
+// noteModulesError tells why the modules of the working directory could not be read.
+// They are extra context next to the provider schemas, so the schemas are shown anyway.
+func (m *Model) noteModulesError(err error) {
+	if err != nil {
+		m.status.SetNotice("modules not loaded: " + strings.SplitN(err.Error(), "\n", 2)[0])
+	}
+}
+
+// refuseNestedModule reports that the outputs of a module called by another module
+// cannot be referenced from the root module
+func (m *Model) refuseNestedModule(mod config.Module) tea.Cmd {
+	m.status.SetCopyStatus(fmt.Sprintf("✗ %s is not called by the root module", mod.Key), "error")
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return copyStatusMsg{}
+	})
+}
+
+// newModuleItem describes a module as an entity of the Modules category
+func newModuleItem(mod config.Module) EntityItem {
+	variables := &tfjson.Schema{Block: &tfjson.SchemaBlock{Attributes: map[string]*tfjson.SchemaAttribute{}}}
+	outputs := &tfjson.Schema{Block: &tfjson.SchemaBlock{Attributes: map[string]*tfjson.SchemaAttribute{}}}
+	defaults := make(map[string]interface{})
+
+	for _, v := range mod.Variables {
+		variables.Block.Attributes[v.Name] = &tfjson.SchemaAttribute{
+			AttributeType: variableType(v.Type),
+			Description:   v.Description,
+			Required:      v.Required,
+			Optional:      !v.Required,
+			Sensitive:     v.Sensitive,
+		}
+		if !v.Required {
+			defaults[v.Name] = v.Default
+		}
+	}
+	for _, o := range mod.Outputs {
+		outputs.Block.Attributes[o.Name] = &tfjson.SchemaAttribute{
+			AttributeType: cty.DynamicPseudoType, // outputs declare no type
+			Description:   o.Description,
+			Computed:      true,
+			Sensitive:     o.Sensitive,
+		}
+	}
+
+	return EntityItem{
+		name:   mod.Key,
+		schema: variables,
+		module: &moduleEntity{module: mod, outputs: outputs, defaults: defaults},
+	}
+}
+
+// summary describes a module in the entity list by its interface and source
+func (e *moduleEntity) summary() string {
+	source := e.module.Source
+	if e.module.Version != "" {
+		source += " " + e.module.Version
+	}
+	if e.module.Err != nil && len(e.module.Variables)+len(e.module.Outputs) == 0 {
+		return "not readable · " + source
+	}
+	return fmt.Sprintf("%d variables, %d outputs · %s", len(e.module.Variables), len(e.module.Outputs), source)
+}
+
+// schemaFor returns the schema shown in the given tree mode
+func (e *moduleEntity) schemaFor(mode ViewMode, variables *tfjson.Schema) *tfjson.Schema {
+	if mode == AttributesMode {
+		return e.outputs
+	}
+	return variables
+}
+
+// note returns the extra label text of a variable or output: its default and whether
+// it is sensitive
+func (e *moduleEntity) note(mode ViewMode, name string, attr *tfjson.SchemaAttribute) string {
+	var parts []string
+	if def, ok := e.defaults[name]; ok && mode == ArgumentsMode {
+		parts = append(parts, "= "+renderDefault(def))
+	}
+	if attr.Sensitive {
+		parts = append(parts, "[sensitive]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// variableType parses the type constraint of a variable; variables without one, or
+// with one that cannot be parsed, accept any value
+func variableType(constraint string) cty.Type {
+	if constraint == "" {
+		return cty.DynamicPseudoType
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(constraint), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.DynamicPseudoType
+	}
+	ty, _, diags := typeexpr.TypeConstraintWithDefaults(expr)
+	if diags.HasErrors() {
+		return cty.DynamicPseudoType
+	}
+	return ty
+}
+
+// renderDefault renders a variable default as a short JSON value
+func renderDefault(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "…"
+	}
+	if r := []rune(string(data)); len(r) > maxDefaultLength {
+		return string(r[:maxDefaultLength-1]) + "…"
+	}
+	return string(data)
+}
+
+// defaultExpressions returns the defaults of the optional variables as HCL expressions,
+// for variables exported from the module
+func (e *moduleEntity) defaultExpressions() map[string]string {
+	exprs := make(map[string]string, len(e.defaults))
+	for name, value := range e.defaults {
+		exprs[name] = defaultExpression(value)
+	}
+	return exprs
+}
+
+// defaultExpression renders a variable default as an HCL expression. A default that
+// cannot be converted is exported as null.
+func defaultExpression(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	ty, err := ctyjson.ImpliedType(data)
+	if err != nil {
+		return "null"
+	}
+	v, err := ctyjson.Unmarshal(data, ty)
+	if err != nil {
+		return "null"
+	}
+	return string(hclwrite.Format(hclwrite.TokensForValue(v).Bytes()))
+}
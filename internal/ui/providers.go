@@ -162,6 +162,17 @@ func (m ProvidersModel) SelectedProvider() (string, *tfjson.ProviderSchema) {
 	return "", nil
 }
 
+// SelectProvider moves the list cursor to the named provider
+func (m *ProvidersModel) SelectProvider(name string) bool {
+	for i, item := range m.list.Items() {
+		if p, ok := item.(ProviderItem); ok && p.name == name {
+			m.list.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
 // Update handles messages for the providers model
 func (m ProvidersModel) Update(msg tea.Msg) (ProvidersModel, tea.Cmd) {
 	if !m.focused {
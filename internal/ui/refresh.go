@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+// schemaRefreshMsg carries schemas fetched in the background after a session opened
+// from the cache, together with a summary of what changed
+type schemaRefreshMsg struct {
+	workingDir string
+	loaded     schemaLoadedMsg
+	changes    []string
+	err        error
+}
+
+// refreshSchemaCmd refetches schemas from the tool and compares them with the ones on
+// screen. The comparison runs in the command so large schemas never block the UI.
+func refreshSchemaCmd(workingDir string, tfInfo terraform.TerraformInfo, current *tfjson.ProviderSchemas, currentVersions map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		schemaWithVersion, err := terraform.RefreshProviderSchemas(workingDir, tfInfo)
+		if err != nil {
+			return schemaRefreshMsg{workingDir: workingDir, err: err}
+		}
+
+		loaded := newSchemaLoadedMsg(schemaWithVersion)
+		return schemaRefreshMsg{
+			workingDir: workingDir,
+			loaded:     loaded,
+			changes:    describeSchemaChanges(current, loaded.schemas, currentVersions, loaded.versions),
+		}
+	}
+}
+
+// describeSchemaChanges lists changed providers as "aws 6.1.0 → 6.2.0", "aws changed",
+// "+random 3.6.0" or "-null"
+func describeSchemaChanges(old, new *tfjson.ProviderSchemas, oldVersions, newVersions map[string]string) []string {
+	names := make(map[string]bool)
+	for name := range old.Schemas {
+		names[name] = true
+	}
+	for name := range new.Schemas {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []string
+	for _, name := range sorted {
+		label := providerShortName(name)
+		before, hadBefore := old.Schemas[name]
+		after, hasAfter := new.Schemas[name]
+		switch {
+		case !hadBefore:
+			changes = append(changes, strings.TrimSpace("+"+label+" "+newVersions[name]))
+		case !hasAfter:
+			changes = append(changes, "-"+label)
+		case oldVersions[name] != "" && newVersions[name] != "" && oldVersions[name] != newVersions[name]:
+			changes = append(changes, fmt.Sprintf("%s %s → %s", label, oldVersions[name], newVersions[name]))
+		case !reflect.DeepEqual(before, after):
+			changes = append(changes, label+" changed")
+		}
+	}
+	return changes
+}
+
+// providerShortName returns the type part of a provider address, e.g. aws for
+// registry.terraform.io/hashicorp/aws
+func providerShortName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// handleSchemaRefresh offers refreshed schemas that differ from the ones on screen
+func (m *Model) handleSchemaRefresh(msg schemaRefreshMsg) {
+	// A failed refresh keeps the cached schemas; they are still the best we have. The
+	// refresh of a workspace that is no longer open is dropped.
+	if msg.err != nil || len(msg.changes) == 0 || msg.workingDir != m.workingDir() {
+		return
+	}
+	m.pendingRefresh = &msg.loaded
+	m.status.SetNotice(fmt.Sprintf("schemas updated: %s • R reload", strings.Join(msg.changes, ", ")))
+}
+
+// applyRefresh swaps in the pending refreshed schemas, keeping the selected provider,
+// category, entity and tree position wherever they still exist. It reports false when
+// the selection no longer exists and the view moved back.
+func (m *Model) applyRefresh() bool {
+	loaded := m.pendingRefresh
+	m.pendingRefresh = nil
+	m.status.ClearNotice()
+	if loaded == nil {
+		return true
+	}
+
+	m.schemas = loaded.schemas
+	m.toolInfo = loaded.toolInfo
+	m.version = loaded.version
+	m.providerVersions = loaded.versions
+	m.providers.SetSchemas(loaded.schemas)
+	m.types.SetToolInfo(loaded.toolInfo, loaded.version)
+	m.status.SetToolInfo(loaded.toolInfo, loaded.version)
+
+	if m.stage == StageProviderSelect || m.selectedProvider == "" {
+		return true
+	}
+
+	providerSchema, ok := loaded.schemas.Schemas[m.selectedProvider]
+	if !ok {
+		m.status.SetCopyStatus(fmt.Sprintf("✗ %s is no longer installed", providerShortName(m.selectedProvider)), "error")
+		m.selectedProvider = ""
+		m.selectedEntity = ""
+		m.stage = StageProviderSelect
+		m.focus = FocusProviders
+		m.types.Blur()
+		m.entities.Blur()
+		m.tree.Blur()
+		m.providers.Focus()
+		m.status.SetProvider("")
+		m.status.SetResourceType("")
+		m.updateLayout()
+		return false
+	}
+
+	cursorType, _ := m.types.SelectedType()
+	m.providers.SelectProvider(m.selectedProvider)
+	m.types.SetCounts(
+		len(providerSchema.DataSourceSchemas),
+		len(providerSchema.ResourceSchemas),
+		len(providerSchema.EphemeralResourceSchemas),
+		len(providerSchema.Functions),
+	)
+	m.types.SelectType(cursorType)
+	if m.stage == StageTypeSelect {
+		return true
+	}
+
+	// Keep the entity under the cursor, which in the browse stage need not be selectedEntity
+	cursorEntity, _ := m.entities.SelectedEntity()
+	m.entities.SetProvider(m.selectedProvider, providerSchema)
+	m.entities.SetType(m.selectedType)
+	m.entities.SelectEntity(cursorEntity)
+	if m.stage == StageEntityBrowse {
+		return true
+	}
+
+	if !m.entities.SelectEntity(m.selectedEntity) {
+		m.status.SetCopyStatus(fmt.Sprintf("✗ %s is no longer in %s", m.selectedEntity, providerShortName(m.selectedProvider)), "error")
+		m.selectedEntity = ""
+		m.exportResult = ""
+		m.status.SetHelpText("")
+		m.stage = StageEntityBrowse
+		m.focus = FocusEntities
+		m.tree.Blur()
+		m.entities.Focus()
+		m.updateLayout()
+		return false
+	}
+	_, entitySchema := m.entities.SelectedEntity()
+	m.tree.SetModule(m.entities.SelectedModule())
+	m.tree.ReplaceSchema(entitySchema)
+	return true
+}
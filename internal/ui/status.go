@@ -34,11 +34,13 @@ type StatusBar struct {
 	width           int
 	toolInfo        terraform.TerraformInfo
 	version         string
+	workspace       string
 	provider        string
 	resourceType    string
 	filter          string
 	copyMessage     string
 	copyMessageType string // "success" or "error"
+	notice          string // persistent message shown when no copy status is set
 	helpText        string
 }
 
@@ -60,6 +62,11 @@ func (s *StatusBar) SetToolInfo(toolInfo terraform.TerraformInfo, version string
 	s.version = version
 }
 
+// SetWorkspace updates the open workspace of a monorepo
+func (s *StatusBar) SetWorkspace(workspace string) {
+	s.workspace = workspace
+}
+
 // SetProvider updates the current provider
 func (s *StatusBar) SetProvider(provider string) {
 	s.provider = provider
@@ -87,6 +94,16 @@ func (s *StatusBar) ClearCopyStatus() {
 	s.copyMessageType = ""
 }
 
+// SetNotice sets a message that stays visible until cleared
+func (s *StatusBar) SetNotice(notice string) {
+	s.notice = notice
+}
+
+// ClearNotice clears the notice
+func (s *StatusBar) ClearNotice() {
+	s.notice = ""
+}
+
 // SetHelpText updates the help text
 func (s *StatusBar) SetHelpText(helpText string) {
 	s.helpText = helpText
@@ -105,6 +122,11 @@ func (s StatusBar) Render() string {
 		parts = append(parts, toolText)
 	}
 
+	// Workspace
+	if s.workspace != "" {
+		parts = append(parts, statusKeyStyle.Render("workspace")+"="+statusValueStyle.Render(s.workspace))
+	}
+
 	// Provider
 	if s.provider != "" {
 		providerText := statusKeyStyle.Render("provider") + "=" + statusValueStyle.Render(s.provider)
@@ -136,7 +158,7 @@ func (s StatusBar) Render() string {
 		leftContent += part
 	}
 
-	// Build center content (copy status)
+	// Build center content (copy status, or the notice)
 	centerContent := ""
 	if s.copyMessage != "" {
 		var copyStyle lipgloss.Style
@@ -150,6 +172,11 @@ func (s StatusBar) Render() string {
 				Bold(true)
 		}
 		centerContent = copyStyle.Render(s.copyMessage)
+	} else if s.notice != "" {
+		centerContent = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")). // Yellow
+			Bold(true).
+			Render(s.notice)
 	}
 
 	// Build right content (help text)
@@ -38,7 +38,8 @@ type SchemaTreeModel struct {
 	nodePathMap  map[string][]string // maps node IDs to paths
 	pathToNodeID map[string]string   // reverse mapping
 	currentIndex int                 // for generating unique node IDs
-	nodeIsBlock  map[string]bool     // track node type for cascade selection
+	nodeIsBlock  map[string]bool     // blocks and nested attributes, which select their descendants
+	module       *moduleEntity       // set while showing a module instead of a provider entity
 }
 
 // NewSchemaTreeModel creates a new schema tree model
@@ -71,6 +72,51 @@ func (m *SchemaTreeModel) SetSchema(entityName string, schema *tfjson.Schema) {
 	m.rebuildTree()
 }
 
+// SetModule makes the tree show the variables and outputs of a module, given before
+// its variables schema is set; nil returns to provider entities
+func (m *SchemaTreeModel) SetModule(module *moduleEntity) {
+	m.module = module
+}
+
+// modeSchema returns the schema shown in the current view mode. It differs from the
+// entity schema only for modules, whose outputs are a schema of their own.
+func (m SchemaTreeModel) modeSchema() *tfjson.Schema {
+	if m.module != nil {
+		return m.module.schemaFor(m.mode, m.schema)
+	}
+	return m.schema
+}
+
+// ReplaceSchema swaps in a new schema for the current entity, keeping the view mode,
+// the cursor, the selection and the folded nodes wherever their paths still exist
+func (m *SchemaTreeModel) ReplaceSchema(schema *tfjson.Schema) {
+	cursor := m.nodePathMap[m.treeModel.GetCurrentNode()]
+	selected := m.GetSelectedPaths()
+	var collapsed [][]string
+	for nodeID, path := range m.nodePathMap {
+		if m.treeModel.IsCollapsed(nodeID) {
+			collapsed = append(collapsed, path)
+		}
+	}
+
+	m.schema = schema
+	m.rebuildTree()
+
+	for _, path := range collapsed {
+		if nodeID, ok := m.pathToNodeID[m.pathKey(path)]; ok {
+			m.treeModel.SetCollapsed(nodeID, true)
+		}
+	}
+	for _, path := range selected {
+		if nodeID, ok := m.pathToNodeID[m.pathKey(path)]; ok {
+			m.treeModel.SetSelection(nodeID, true)
+		}
+	}
+	if nodeID, ok := m.pathToNodeID[m.pathKey(cursor)]; ok && cursor != nil {
+		m.treeModel.SetCursorNode(nodeID)
+	}
+}
+
 // ToggleMode switches between Arguments and Attributes view
 func (m *SchemaTreeModel) ToggleMode() {
 	if m.mode == ArgumentsMode {
@@ -108,13 +154,7 @@ func (m *SchemaTreeModel) rebuildTree() {
 		for _, name := range sortedAttrKeys(m.schema.Block.Attributes) {
 			attr := m.schema.Block.Attributes[name]
 			if !attr.Computed { // Arguments are non-computed
-				path := []string{name}
-				nodeID := m.generateNodeID()
-				schemaNode := tree.NewAttributeNode(nodeID, name, attr, path)
-				m.nodePathMap[nodeID] = path
-				m.pathToNodeID[m.pathKey(path)] = nodeID
-				m.treeModel.Add("", nodeID, schemaNode)
-				m.nodeIsBlock[nodeID] = false
+				m.addAttributeNodes("", name, attr, []string{name})
 			}
 		}
 
@@ -122,60 +162,83 @@ func (m *SchemaTreeModel) rebuildTree() {
 		for _, name := range sortedBlockKeys(m.schema.Block.NestedBlocks) {
 			block := m.schema.Block.NestedBlocks[name]
 			path := []string{name}
-			m.addBlockNodes("", name, block.Block, path)
+			m.addBlockNodes("", name, block, path)
 		}
 
 	case AttributesMode:
 		// Show computed attributes
-		for _, name := range sortedAttrKeys(m.schema.Block.Attributes) {
-			attr := m.schema.Block.Attributes[name]
+		schema := m.modeSchema()
+		for _, name := range sortedAttrKeys(schema.Block.Attributes) {
+			attr := schema.Block.Attributes[name]
 			if attr.Computed {
-				path := []string{name}
-				nodeID := m.generateNodeID()
-				schemaNode := tree.NewAttributeNode(nodeID, name, attr, path)
-				m.nodePathMap[nodeID] = path
-				m.pathToNodeID[m.pathKey(path)] = nodeID
-				m.treeModel.Add("", nodeID, schemaNode)
-				m.nodeIsBlock[nodeID] = false
+				m.addAttributeNodes("", name, attr, []string{name})
 			}
 		}
 
 		// Computed nested blocks are less common but possible
-		for _, name := range sortedBlockKeys(m.schema.Block.NestedBlocks) {
-			block := m.schema.Block.NestedBlocks[name]
+		for _, name := range sortedBlockKeys(schema.Block.NestedBlocks) {
+			block := schema.Block.NestedBlocks[name]
 			path := []string{name}
-			m.addBlockNodes("", name, block.Block, path)
+			m.addBlockNodes("", name, block, path)
 		}
 	}
 }
 
 // addBlockNodes recursively adds block nodes and their children
-func (m *SchemaTreeModel) addBlockNodes(parentID, name string, block *tfjson.SchemaBlock, path []string) {
+func (m *SchemaTreeModel) addBlockNodes(parentID, name string, blockType *tfjson.SchemaBlockType, path []string) {
 	nodeID := m.generateNodeID()
-	schemaNode := tree.NewBlockNode(nodeID, name, block, path)
+	schemaNode := tree.NewBlockNode(nodeID, name, blockType, path)
 	m.nodePathMap[nodeID] = path
 	m.pathToNodeID[m.pathKey(path)] = nodeID
 	m.treeModel.Add(parentID, nodeID, schemaNode)
 	m.nodeIsBlock[nodeID] = true
+	block := blockType.Block
 
 	// Add attributes from the nested block (sorted)
 	for _, attrName := range sortedAttrKeys(block.Attributes) {
 		attr := block.Attributes[attrName]
-		childPath := append(path, attrName)
-		childNodeID := m.generateNodeID()
-		childSchemaNode := tree.NewAttributeNode(childNodeID, attrName, attr, childPath)
-		m.nodePathMap[childNodeID] = childPath
-		m.pathToNodeID[m.pathKey(childPath)] = childNodeID
-		m.treeModel.Add(nodeID, childNodeID, childSchemaNode)
-		m.nodeIsBlock[childNodeID] = false
+		childPath := append(append([]string(nil), path...), attrName)
+		m.addAttributeNodes(nodeID, attrName, attr, childPath)
 	}
 
 	// Add nested blocks recursively (sorted)
 	for _, blockName := range sortedBlockKeys(block.NestedBlocks) {
 		nestedBlock := block.NestedBlocks[blockName]
-		childPath := append(path, blockName)
-		m.addBlockNodes(nodeID, blockName, nestedBlock.Block, childPath)
+		childPath := append(append([]string(nil), path...), blockName)
+		m.addBlockNodes(nodeID, blockName, nestedBlock, childPath)
+	}
+}
+
+// addAttributeNodes adds an attribute node and, for attributes of plugin framework
+// providers that nest attributes instead of declaring a type, the nested attributes
+// below it. Like blocks, such attributes select their nested attributes with them.
+func (m *SchemaTreeModel) addAttributeNodes(parentID, name string, attr *tfjson.SchemaAttribute, path []string) {
+	nodeID := m.generateNodeID()
+	schemaNode := m.newAttributeNode(nodeID, name, attr, path)
+	m.nodePathMap[nodeID] = path
+	m.pathToNodeID[m.pathKey(path)] = nodeID
+	m.treeModel.Add(parentID, nodeID, schemaNode)
+	m.nodeIsBlock[nodeID] = attr.AttributeNestedType != nil
+
+	if attr.AttributeNestedType == nil {
+		return
 	}
+	for _, childName := range sortedAttrKeys(attr.AttributeNestedType.Attributes) {
+		childPath := append(append([]string(nil), path...), childName)
+		m.addAttributeNodes(nodeID, childName, attr.AttributeNestedType.Attributes[childName], childPath)
+	}
+}
+
+// newAttributeNode creates the node of an attribute, noting the default and sensitivity
+// of module variables and outputs in its label
+func (m *SchemaTreeModel) newAttributeNode(id, name string, attr *tfjson.SchemaAttribute, path []string) *tree.SchemaNode {
+	node := tree.NewAttributeNode(id, name, attr, path)
+	if m.module != nil {
+		if note := m.module.note(m.mode, name, attr); note != "" {
+			node.AppendNote(note)
+		}
+	}
+	return node
 }
 
 // sortedAttrKeys returns attribute map keys sorted alphabetically
@@ -245,6 +308,28 @@ func (m *SchemaTreeModel) pathKey(path []string) string {
 	return strings.Join(path, ".")
 }
 
+// FocusPath places the cursor on the node at the given attribute path. When the path
+// only exists in the other view mode, the tree switches to that mode first.
+func (m *SchemaTreeModel) FocusPath(path []string) bool {
+	key := m.pathKey(path)
+	if nodeID, ok := m.pathToNodeID[key]; ok {
+		return m.treeModel.SetCursorNode(nodeID)
+	}
+
+	m.ToggleMode()
+	if nodeID, ok := m.pathToNodeID[key]; ok {
+		return m.treeModel.SetCursorNode(nodeID)
+	}
+	m.ToggleMode()
+	return false
+}
+
+// CurrentNode returns the schema node under the cursor, or nil when the tree is empty
+func (m SchemaTreeModel) CurrentNode() *tree.SchemaNode {
+	node, _ := m.treeModel.GetNode(m.treeModel.GetCurrentNode()).(*tree.SchemaNode)
+	return node
+}
+
 // Focus sets focus on the tree
 func (m *SchemaTreeModel) Focus() {
 	m.focused = true
@@ -302,6 +387,36 @@ func (m *SchemaTreeModel) MovePageUp() {
 	m.treeModel.MovePageUp()
 }
 
+// CollapseCurrent folds the node under the cursor or, when it has nothing to fold,
+// moves the cursor to its parent
+func (m *SchemaTreeModel) CollapseCurrent() {
+	current := m.treeModel.GetCurrentNode()
+	if current == "" {
+		return
+	}
+	if m.treeModel.HasChildren(current) && !m.treeModel.IsCollapsed(current) {
+		m.treeModel.SetCollapsed(current, true)
+		return
+	}
+	if parent := m.treeModel.GetParent(current); parent != "" {
+		m.treeModel.SetCursorNode(parent)
+	}
+}
+
+// ExpandCurrent unfolds the node under the cursor or, when it is already unfolded,
+// moves the cursor to its first child
+func (m *SchemaTreeModel) ExpandCurrent() {
+	current := m.treeModel.GetCurrentNode()
+	if current == "" || !m.treeModel.HasChildren(current) {
+		return
+	}
+	if m.treeModel.IsCollapsed(current) {
+		m.treeModel.SetCollapsed(current, false)
+		return
+	}
+	m.treeModel.MoveDown()
+}
+
 // Update handles messages for the tree model
 func (m SchemaTreeModel) Update(msg tea.Msg) (SchemaTreeModel, tea.Cmd) {
 	if !m.focused {
@@ -348,6 +463,21 @@ func (m SchemaTreeModel) Update(msg tea.Msg) (SchemaTreeModel, tea.Cmd) {
 		case "a": // Toggle arguments/attributes
 			m.ToggleMode()
 			return m, nil
+		case "h", "left": // Fold the current node
+			m.CollapseCurrent()
+			return m, nil
+		case "l", "right": // Unfold the current node
+			m.ExpandCurrent()
+			return m, nil
+		case "+": // Unfold everything
+			m.treeModel.ExpandAll()
+			return m, nil
+		case "-": // Fold everything down to the top-level nodes
+			m.treeModel.CollapseAll()
+			return m, nil
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9": // Show this many levels
+			m.treeModel.ExpandToDepth(int(keyMsg.Runes[0] - '0'))
+			return m, nil
 		}
 	}
 
@@ -382,7 +512,7 @@ func (m SchemaTreeModel) View() string {
 	if len(selectedPaths) > 0 {
 		instructions = fmt.Sprintf("Selected: %d nodes (press 'e' to export, esc to clear)", len(selectedPaths))
 	} else {
-		instructions = "↑/↓ or j/k to navigate, space to select, ctrl+a to select all, 'a' to toggle mode"
+		instructions = "↑/↓ or j/k to navigate, h/l to fold, space to select, ctrl+a to select all, 'a' to toggle mode, 'd' for details"
 	}
 
 	return fmt.Sprintf("%s\n%s\n%s", title, treeView, instructions)
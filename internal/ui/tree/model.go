@@ -19,6 +19,7 @@ package tree
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -38,6 +39,7 @@ type Model struct {
 
 	// Selection and scrolling support (extensions)
 	selected    map[string]bool // tracks which nodes are selected
+	collapsed   map[string]bool // nodes whose children are folded away
 	viewport    int             // current scroll position
 	height      int             // available display height
 	totalHeight int             // total content height
@@ -56,11 +58,12 @@ type Model struct {
 
 func NewModel() Model {
 	return Model{
-		nodes:    make(map[string]VisibleModel),
-		children: make(map[string][]string),
-		parents:  make(map[string]string),
-		selected: make(map[string]bool), // extension
-		lock:     &sync.RWMutex{},
+		nodes:     make(map[string]VisibleModel),
+		children:  make(map[string][]string),
+		parents:   make(map[string]string),
+		selected:  make(map[string]bool), // extension
+		collapsed: make(map[string]bool), // extension
+		lock:      &sync.RWMutex{},
 
 		// formatting options
 		Margin:                    "",
@@ -121,8 +124,9 @@ func (m Model) GetVisibleNodes() []string {
 	return m.getVisibleNodesUnsafe()
 }
 
-// collectVisibleNodes recursively collects visible node IDs
-func (m Model) collectVisibleNodes(id string, observed *strset.Set, result *[]string) {
+// collectVisibleNodes recursively collects visible node IDs, skipping the children of
+// collapsed nodes unless includeCollapsed is set
+func (m Model) collectVisibleNodes(id string, observed *strset.Set, result *[]string, includeCollapsed bool) {
 	if observed.Has(id) {
 		return
 	}
@@ -131,14 +135,24 @@ func (m Model) collectVisibleNodes(id string, observed *strset.Set, result *[]st
 	node := m.nodes[id]
 	if node.IsVisible() {
 		*result = append(*result, id)
+		if m.collapsed[id] && !includeCollapsed {
+			return
+		}
 
 		// Add children
 		for _, childID := range m.children[id] {
-			m.collectVisibleNodes(childID, observed, result)
+			m.collectVisibleNodes(childID, observed, result, includeCollapsed)
 		}
 	}
 }
 
+// GetNode returns the model of the node with the given ID, or nil if there is none (extension)
+func (m Model) GetNode(id string) VisibleModel {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.nodes[id]
+}
+
 // GetCurrentNode returns the node ID at the cursor position (extension)
 func (m Model) GetCurrentNode() string {
 	visibleNodes := m.GetVisibleNodes()
@@ -148,28 +162,133 @@ func (m Model) GetCurrentNode() string {
 	return ""
 }
 
+// SetCursorNode moves the cursor to the given node, unfolding its ancestors, and scrolls
+// it into view (extension)
+func (m *Model) SetCursorNode(id string) bool {
+	if _, ok := m.nodes[id]; !ok {
+		return false
+	}
+	for parent, ok := m.parents[id]; ok; parent, ok = m.parents[parent] {
+		delete(m.collapsed, parent)
+	}
+	for i, visibleID := range m.getVisibleNodesUnsafe() {
+		if visibleID == id {
+			m.cursor = i
+			m.Clamp()
+			return true
+		}
+	}
+	return false
+}
+
 // getVisibleNodesUnsafe returns visible nodes without acquiring locks.
 // Callers must ensure appropriate synchronization.
 func (m Model) getVisibleNodesUnsafe() []string {
 	var visibleNodes []string
 	observed := strset.New()
 	for _, id := range m.roots {
-		m.collectVisibleNodes(id, observed, &visibleNodes)
+		m.collectVisibleNodes(id, observed, &visibleNodes, false)
 	}
 	return visibleNodes
 }
 
-// SelectAll selects all visible nodes (extension)
+// SelectAll selects all visible nodes, including the ones folded away (extension)
 func (m *Model) SelectAll() {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	// Avoid deadlock: compute visible nodes without taking another lock
-	visibleNodes := m.getVisibleNodesUnsafe()
-	for _, nodeID := range visibleNodes {
+	var nodes []string
+	observed := strset.New()
+	for _, id := range m.roots {
+		m.collectVisibleNodes(id, observed, &nodes, true)
+	}
+	for _, nodeID := range nodes {
 		m.selected[nodeID] = true
 	}
 }
 
+// IsCollapsed reports whether the children of a node are folded away (extension)
+func (m Model) IsCollapsed(id string) bool {
+	return m.collapsed[id] && m.hasChildren(id)
+}
+
+// GetParent returns the parent of a node, or "" for a root (extension)
+func (m Model) GetParent(id string) string {
+	return m.parents[id]
+}
+
+// HasChildren reports whether a node has visible children, folded or not (extension)
+func (m Model) HasChildren(id string) bool {
+	return m.hasChildren(id)
+}
+
+// SetCollapsed folds or unfolds the children of a node (extension)
+func (m *Model) SetCollapsed(id string, collapsed bool) {
+	m.keepCursor(func() {
+		if collapsed && m.hasChildren(id) {
+			m.collapsed[id] = true
+		} else {
+			delete(m.collapsed, id)
+		}
+	})
+}
+
+// CollapseAll folds every node with children, leaving only the roots shown (extension)
+func (m *Model) CollapseAll() {
+	m.ExpandToDepth(1)
+}
+
+// ExpandAll unfolds every node (extension)
+func (m *Model) ExpandAll() {
+	m.keepCursor(func() {
+		m.collapsed = make(map[string]bool)
+	})
+}
+
+// ExpandToDepth unfolds the nodes above the given depth and folds the ones at it, so
+// that depth levels of the tree are shown; roots are at depth 1 (extension)
+func (m *Model) ExpandToDepth(depth int) {
+	m.keepCursor(func() {
+		m.collapsed = make(map[string]bool)
+		var fold func(ids []string, level int)
+		fold = func(ids []string, level int) {
+			for _, id := range ids {
+				if !m.hasChildren(id) {
+					continue
+				}
+				if level >= depth {
+					m.collapsed[id] = true
+					continue
+				}
+				fold(m.children[id], level+1)
+			}
+		}
+		fold(m.roots, 1)
+	})
+}
+
+// keepCursor applies a change to the folded nodes keeping the cursor on the node it
+// was on or, when that node was folded away, on its closest shown ancestor
+func (m *Model) keepCursor(change func()) {
+	current := m.GetCurrentNode()
+	change()
+	if current == "" {
+		m.Clamp()
+		return
+	}
+	visible := m.getVisibleNodesUnsafe()
+	for id := current; id != ""; id = m.parents[id] {
+		for i, visibleID := range visible {
+			if visibleID == id {
+				m.cursor = i
+				m.Clamp()
+				return
+			}
+		}
+	}
+	m.Clamp()
+}
+
 // ClearSelection clears all selected nodes (extension)
 func (m *Model) ClearSelection() {
 	m.lock.Lock()
@@ -204,7 +323,8 @@ func (m *Model) Remove(id string) {
 	delete(m.nodes, id)
 	delete(m.children, id)
 	delete(m.parents, id)
-	delete(m.selected, id) // extension: clean up selection state
+	delete(m.selected, id)  // extension: clean up selection state
+	delete(m.collapsed, id) // extension: and folding state
 
 	for _, children := range m.children {
 		for i, child := range children {
@@ -490,6 +610,10 @@ func (m Model) renderNodeWithCursor(siblingIdx int, id string, observed *strset.
 	// add the node's view with selection indicator (extension)
 	current := node.View()
 	linesRendered := 0
+	collapsed := m.IsCollapsed(id)
+	if len(current) > 0 && collapsed {
+		current += " " + childCount(m.visibleChildren(id))
+	}
 	if len(current) > 0 {
 		// Add selection checkbox prefix
 		selectionPrefix := "[ ] "
@@ -516,7 +640,10 @@ func (m Model) renderNodeWithCursor(siblingIdx int, id string, observed *strset.
 		*currentLine++
 	}
 
-	// process all children
+	// process all children, unless folded away (extension)
+	if collapsed {
+		return sb.String(), linesRendered
+	}
 	for i, childID := range m.children[id] {
 		_, ok := m.nodes[childID]
 		if ok && !observed.Has(childID) {
@@ -551,6 +678,25 @@ func (m Model) hasChildren(id string) bool {
 	return false
 }
 
+// visibleChildren counts the visible children of a node (extension)
+func (m Model) visibleChildren(id string) int {
+	count := 0
+	for _, childID := range m.children[id] {
+		if m.nodes[childID].IsVisible() {
+			count++
+		}
+	}
+	return count
+}
+
+// childCount renders the number of children folded away below a collapsed node
+func childCount(n int) string {
+	if n == 1 {
+		return "(1 child)"
+	}
+	return fmt.Sprintf("(%d children)", n)
+}
+
 func (m Model) forkOrLeaf(siblingIdx int, id string) string {
 	if parent, exists := m.parents[id]; exists {
 		// index relative to the parent's "children" list
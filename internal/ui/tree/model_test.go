@@ -209,3 +209,69 @@ func TestModel_Scrolling(t *testing.T) {
 	require.Contains(t, scrolledView, "Node 2")
 	require.Contains(t, scrolledView, "Node 3")
 }
+
+func TestModel_Collapse(t *testing.T) {
+	subject := NewModel()
+
+	// ├─ a
+	// │  ├─ a-a
+	// │  └─ a-b
+	// │     └─ a-b-a
+	// └─ b
+	require.NoError(t, subject.Add("", "a", dummyViewer{state: "node-a"}))
+	require.NoError(t, subject.Add("a", "a-a", dummyViewer{state: "node-a-a"}))
+	require.NoError(t, subject.Add("a", "a-b", dummyViewer{state: "node-a-b"}))
+	require.NoError(t, subject.Add("a-b", "a-b-a", dummyViewer{state: "node-a-b-a"}))
+	require.NoError(t, subject.Add("", "b", dummyViewer{state: "node-b"}))
+	subject.ToggleSelection("a-a")
+
+	// Folding a node hides its descendants and shows how many children it has
+	subject.SetCollapsed("a", true)
+	require.Equal(t, []string{"a", "b"}, subject.GetVisibleNodes())
+	require.Contains(t, subject.View(), "node-a (2 children)")
+	require.NotContains(t, subject.View(), "node-a-a")
+	require.True(t, subject.IsSelected("a-a"), "folding keeps the selection")
+
+	// Leaves have nothing to fold
+	subject.SetCollapsed("b", true)
+	require.False(t, subject.IsCollapsed("b"))
+
+	// Moving the cursor into a folded subtree unfolds it
+	require.True(t, subject.SetCursorNode("a-b-a"))
+	require.Equal(t, "a-b-a", subject.GetCurrentNode())
+	require.False(t, subject.IsCollapsed("a"))
+
+	// The cursor moves to the closest shown ancestor of a node folded away
+	subject.CollapseAll()
+	require.Equal(t, []string{"a", "b"}, subject.GetVisibleNodes())
+	require.Equal(t, "a", subject.GetCurrentNode())
+
+	subject.ExpandToDepth(2)
+	require.Equal(t, []string{"a", "a-a", "a-b", "b"}, subject.GetVisibleNodes())
+	require.Contains(t, subject.View(), "node-a-b (1 child)")
+
+	subject.ExpandAll()
+	require.Len(t, subject.GetVisibleNodes(), 5)
+
+	// Selecting all includes the nodes folded away
+	subject.CollapseAll()
+	subject.SelectAll()
+	require.Len(t, subject.GetSelectedNodes(), 5)
+}
+
+func TestModel_CollapseKeepsCursorInView(t *testing.T) {
+	subject := NewModel()
+	subject.SetHeight(3)
+
+	require.NoError(t, subject.Add("", "root", dummyViewer{state: "Root"}))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, subject.Add("root", fmt.Sprintf("child%d", i), dummyViewer{state: fmt.Sprintf("Child %d", i)}))
+	}
+	require.True(t, subject.SetCursorNode("child9"))
+	require.NotContains(t, subject.View(), "Root")
+
+	// Folding the subtree the cursor is in scrolls back to its root
+	subject.SetCollapsed("root", true)
+	require.Equal(t, "root", subject.GetCurrentNode())
+	require.Contains(t, subject.View(), "> [ ] Root (10 children)")
+}
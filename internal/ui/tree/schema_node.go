@@ -10,6 +10,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/zclconf/go-cty/cty"
+
+	"github.com/terraconstructs/provider-explorer/internal/schema"
 )
 
 var (
@@ -28,6 +30,9 @@ var (
 
 	schemaComputedStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("140"))
+
+	schemaNoteStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("244"))
 )
 
 // SchemaNode represents a node in the schema tree that implements VisibleModel
@@ -43,7 +48,7 @@ type SchemaNode struct {
 	attribute *tfjson.SchemaAttribute
 
 	// For blocks
-	block *tfjson.SchemaBlock
+	blockType *tfjson.SchemaBlockType
 }
 
 type SchemaNodeType int
@@ -57,8 +62,8 @@ const (
 func NewAttributeNode(id, name string, attr *tfjson.SchemaAttribute, path []string) *SchemaNode {
 	// Create display text with type and status
 	typeInfo := ""
-	if attr.AttributeType != cty.NilType {
-		typeInfo = fmt.Sprintf(" (%s)", typeFriendlyLabel(attr.AttributeType))
+	if attrType := schema.AttributeType(attr); attrType != cty.NilType {
+		typeInfo = fmt.Sprintf(" (%s)", schema.TypeLabel(attrType))
 	}
 
 	status := ""
@@ -87,38 +92,15 @@ func NewAttributeNode(id, name string, attr *tfjson.SchemaAttribute, path []stri
 	}
 }
 
-// typeFriendlyLabel provides a simple, stable label for cty types for display.
-func typeFriendlyLabel(t cty.Type) string {
-	switch t { // handle primitives exactly
-	case cty.String:
-		return "string"
-	case cty.Number:
-		return "number"
-	case cty.Bool:
-		return "bool"
-	}
-	// collections and other shapes (avoid verbose/unstable formatting)
-	if t.IsListType() {
-		return "list"
-	}
-	if t.IsSetType() {
-		return "set"
-	}
-	if t.IsMapType() {
-		return "map"
-	}
-	if t.IsTupleType() {
-		return "tuple"
-	}
-	if t.IsObjectType() {
-		return "object"
-	}
-	return "any"
+// AppendNote adds dimmed text after the label, e.g. the default of a module variable
+func (n *SchemaNode) AppendNote(note string) {
+	n.displayText += " " + schemaNoteStyle.Render(note)
 }
 
-// NewBlockNode creates a new schema node for a block
-func NewBlockNode(id, name string, block *tfjson.SchemaBlock, path []string) *SchemaNode {
-	displayText := schemaArgumentStyle.Render(name + " [block]")
+// NewBlockNode creates a new schema node for a nested block, labelled with its nesting
+// mode and item limits
+func NewBlockNode(id, name string, blockType *tfjson.SchemaBlockType, path []string) *SchemaNode {
+	displayText := schemaArgumentStyle.Render(name + " [" + schema.BlockLabel(blockType) + "]")
 
 	return &SchemaNode{
 		id:          id,
@@ -127,7 +109,7 @@ func NewBlockNode(id, name string, block *tfjson.SchemaBlock, path []string) *Sc
 		path:        path,
 		nodeType:    BlockNode,
 		visible:     true,
-		block:       block,
+		blockType:   blockType,
 	}
 }
 
@@ -170,7 +152,16 @@ func (n *SchemaNode) GetAttribute() *tfjson.SchemaAttribute {
 }
 
 func (n *SchemaNode) GetBlock() *tfjson.SchemaBlock {
-	return n.block
+	if n.blockType == nil {
+		return nil
+	}
+	return n.blockType.Block
+}
+
+// GetBlockType returns the nested block type, which holds the nesting mode and item
+// limits of the block
+func (n *SchemaNode) GetBlockType() *tfjson.SchemaBlockType {
+	return n.blockType
 }
 
 func (n *SchemaNode) IsAttribute() bool {
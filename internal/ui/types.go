@@ -37,6 +37,7 @@ const (
 	ResourcesType
 	EphemeralResourcesType
 	ProviderFunctionsType
+	ModulesType
 )
 
 // TypeItem represents a resource type in the picker
@@ -92,6 +93,7 @@ type TypesModel struct {
 	focused  bool
 	toolInfo terraform.TerraformInfo
 	version  string
+	modules  int // module count of the workspace; the category is hidden without modules
 }
 
 // NewTypesModel creates a new types model
@@ -152,20 +154,54 @@ func (m *TypesModel) SetCounts(dataSourceCount, resourceCount, ephemeralCount, f
 		TypeItem{
 			name:    "Ephemeral Resources",
 			resType: EphemeralResourcesType,
-			enabled: m.supportsEphemeralResources(),
+			enabled: m.supportsEphemeralResources() || m.presentWithoutVersion(ephemeralCount),
 			count:   ephemeralCount,
 		},
 		TypeItem{
 			name:    "Provider Functions",
 			resType: ProviderFunctionsType,
-			enabled: m.supportsProviderFunctions(),
+			enabled: m.supportsProviderFunctions() || m.presentWithoutVersion(functionCount),
 			count:   functionCount,
 		},
 	}
+	if m.modules > 0 {
+		items = append(items, m.modulesItem())
+	}
 
 	m.list.SetItems(items)
 }
 
+// SetModuleCount shows the Modules category, listing the modules of the workspace,
+// unless count is zero
+func (m *TypesModel) SetModuleCount(count int) {
+	m.modules = count
+	var items []list.Item
+	for _, item := range m.list.Items() {
+		if t, ok := item.(TypeItem); !ok || t.resType != ModulesType {
+			items = append(items, item)
+		}
+	}
+	if count > 0 {
+		items = append(items, m.modulesItem())
+	}
+	m.list.SetItems(items)
+}
+
+// modulesItem returns the item of the Modules category
+func (m TypesModel) modulesItem() TypeItem {
+	return TypeItem{
+		name:    "Modules",
+		resType: ModulesType,
+		enabled: true,
+		count:   m.modules,
+	}
+}
+
+// Len returns the number of categories offered
+func (m TypesModel) Len() int {
+	return len(m.list.Items())
+}
+
 // Focus sets focus on the types list
 func (m *TypesModel) Focus() {
 	m.focused = true
@@ -189,6 +225,20 @@ func (m TypesModel) SelectedType() (ResourceType, bool) {
 	return DataSourcesType, false
 }
 
+// SelectType moves the list cursor to the given type if it is enabled
+func (m *TypesModel) SelectType(resType ResourceType) bool {
+	for i, item := range m.list.Items() {
+		if t, ok := item.(TypeItem); ok && t.resType == resType {
+			if !t.enabled {
+				return false
+			}
+			m.list.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
 // MoveToEnabledItem moves selection to the next enabled item if current is disabled
 func (m *TypesModel) MoveToEnabledItem() {
 	current := m.list.Index()
@@ -250,6 +300,13 @@ func (m TypesModel) supportsProviderFunctions() bool {
 	return m.toolInfo.SupportsFeature(terraform.ProviderFunctions, m.version)
 }
 
+// presentWithoutVersion reports whether a gated type should still be enabled because the tool
+// version is unknown (e.g. a schema file opened without a Terraform binary) but the schema
+// contains entries of that type, which proves the producing tool supported it.
+func (m TypesModel) presentWithoutVersion(count int) bool {
+	return m.version == "" && count > 0
+}
+
 // Update handles messages for the types model
 func (m TypesModel) Update(msg tea.Msg) (TypesModel, tea.Cmd) {
 	if !m.focused {
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/terraconstructs/provider-explorer/internal/config"
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+// workspaceChangedMsg is sent when the watched working directory changed
+type workspaceChangedMsg struct{}
+
+// workspaceReloadedMsg carries schemas reloaded after a workspace change
+type workspaceReloadedMsg struct {
+	loaded schemaLoadedMsg
+	err    error
+}
+
+// EnableWatch makes the session reload schemas whenever the lockfile, the installed
+// providers or the configuration files of the working directory change. Watching stops
+// when ctx is cancelled.
+func (m *Model) EnableWatch(ctx context.Context, interval time.Duration) {
+	m.watchCh = terraform.WatchWorkspace(ctx, m.workingDir(), interval)
+}
+
+// waitForWorkspaceChange delivers the next change reported by the watcher
+func waitForWorkspaceChange(changes <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-changes; !ok {
+			return nil
+		}
+		return workspaceChangedMsg{}
+	}
+}
+
+// reloadWorkspaceCmd drops the cached schemas of the working directory and loads them again
+func reloadWorkspaceCmd(workingDir string, tfInfo terraform.TerraformInfo) tea.Cmd {
+	return func() tea.Msg {
+		if err := terraform.InvalidateCache(workingDir, tfInfo); err != nil {
+			return workspaceReloadedMsg{err: err}
+		}
+		schemaWithVersion, err := terraform.FetchAllProviderSchemas(workingDir, tfInfo)
+		if err != nil {
+			return workspaceReloadedMsg{err: err}
+		}
+		loaded := newSchemaLoadedMsg(schemaWithVersion)
+		loaded.modules, loaded.modulesErr = config.LoadModules(workingDir)
+		return workspaceReloadedMsg{loaded: loaded}
+	}
+}
+
+// handleWorkspaceChanged reloads schemas after a change and keeps watching
+func (m *Model) handleWorkspaceChanged() tea.Cmd {
+	next := waitForWorkspaceChange(m.watchCh)
+
+	switch m.stage {
+	case StageLoading, StageInitConfirm, StageInitRunning:
+		// A load or init is already under way; it will see the new state
+		return next
+	case StageWorkspaceSelect:
+		// Nothing is loaded yet
+		return next
+	case StageError:
+		// The change may well be the fix, e.g. init run in another terminal
+		if err := terraform.InvalidateCache(m.workingDir(), m.toolInfo); err != nil {
+			m.errorNotice = err.Error()
+			return next
+		}
+		return tea.Batch(m.retryLoad(), next)
+	}
+
+	m.status.SetCopyStatus("⟳ workspace changed, reloading schemas…", "success")
+	return tea.Batch(reloadWorkspaceCmd(m.workingDir(), m.toolInfo), next)
+}
+
+// handleWorkspaceReloaded swaps in the reloaded schemas, keeping the current selection
+// where it still exists. A failed reload keeps the schemas on screen.
+func (m *Model) handleWorkspaceReloaded(msg workspaceReloadedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.status.SetCopyStatus("✗ reload failed: "+strings.SplitN(msg.err.Error(), "\n", 2)[0], "error")
+	} else {
+		// Module changes are only picked up here; a background refresh of cached schemas
+		// leaves the configuration alone
+		m.setModules(msg.loaded.modules)
+		m.pendingRefresh = &msg.loaded
+		if m.applyRefresh() {
+			m.status.SetCopyStatus("✓ schemas reloaded", "success")
+		}
+		m.noteModulesError(msg.loaded.modulesErr)
+	}
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return copyStatusMsg{}
+	})
+}
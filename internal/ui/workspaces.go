@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/config"
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+)
+
+// WorkspaceItem is a root module offered by the workspace picker
+type WorkspaceItem struct {
+	path      string // relative to the monorepo root
+	providers string // locked providers, or why there are none
+}
+
+// FilterValue implements list.Item
+func (i WorkspaceItem) FilterValue() string { return i.path }
+
+// newWorkspaceItem describes a root module by the providers its lockfile selects
+func newWorkspaceItem(root, path string) WorkspaceItem {
+	item := WorkspaceItem{path: path}
+	selections, err := terraform.ReadLockfileSelections(filepath.Join(root, filepath.FromSlash(path)))
+	if err != nil || len(selections) == 0 {
+		item.providers = "no lockfile, not initialized"
+		return item
+	}
+	var names []string
+	for source, version := range selections {
+		names = append(names, providerShortName(source)+" "+version)
+	}
+	sort.Strings(names)
+	item.providers = strings.Join(names, ", ")
+	return item
+}
+
+// workspaceDelegate renders workspaces like providers: path, then locked providers
+type workspaceDelegate struct{}
+
+func (d workspaceDelegate) Height() int                               { return 2 }
+func (d workspaceDelegate) Spacing() int                              { return 1 }
+func (d workspaceDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
+func (d workspaceDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(WorkspaceItem)
+	if !ok {
+		return
+	}
+
+	str := fmt.Sprintf("%s\n%s", i.path, i.providers)
+	if index == m.Index() {
+		fmt.Fprint(w, providerSelectedItemStyle.Render("> "+str))
+	} else {
+		fmt.Fprint(w, providerItemStyle.Render("  "+str))
+	}
+}
+
+// SetWorkspaces starts the session on a picker over the root modules of a monorepo,
+// given relative to root. Schemas are loaded from the picked workspace, which is
+// initialized first when needed, without confirmation if autoInit is set. Esc on the
+// provider list, or w on the error screen, returns to the picker.
+func (m *Model) SetWorkspaces(root string, workspaces []string, autoInit bool) {
+	items := make([]list.Item, len(workspaces))
+	for i, path := range workspaces {
+		items[i] = newWorkspaceItem(root, path)
+	}
+	l := list.New(items, workspaceDelegate{}, m.width, m.height-2)
+	l.Title = fmt.Sprintf("Workspaces in %s", root)
+	l.SetShowStatusBar(false)
+	l.SetShowPagination(false)
+	l.SetFilteringEnabled(true)
+	l.Styles.Title = providerTitleStyle
+	l.SetShowHelp(false)
+
+	m.workspaceRoot = root
+	m.workspaceList = l
+	m.workspaceAutoInit = autoInit
+	m.showWorkspacePicker()
+}
+
+// hasWorkspaces reports whether the session was started on the workspace picker
+func (m Model) hasWorkspaces() bool {
+	return m.workspaceRoot != ""
+}
+
+// workingDir returns the directory schemas are loaded from: the picked workspace, or
+// the current directory outside monorepo mode
+func (m Model) workingDir() string {
+	if m.workspace == "" {
+		return "."
+	}
+	return filepath.Join(m.workspaceRoot, filepath.FromSlash(m.workspace))
+}
+
+// showWorkspacePicker returns to the picker, dropping the schemas of the last workspace
+func (m *Model) showWorkspacePicker() {
+	m.stage = StageWorkspaceSelect
+	m.focus = FocusProviders
+	m.workspace = ""
+	m.selectedProvider = ""
+	m.selectedEntity = ""
+	m.pendingRefresh = nil
+	m.loadErr = nil
+	m.providers.Blur()
+	m.types.Blur()
+	m.entities.Blur()
+	m.tree.Blur()
+	m.status.SetWorkspace("")
+	m.status.SetProvider("")
+	m.status.SetResourceType("")
+	m.status.ClearNotice()
+}
+
+// handleWorkspaceKey handles the workspace picker; the list handles its own filtering
+func (m *Model) handleWorkspaceKey(msg tea.KeyMsg) tea.Cmd {
+	if m.workspaceList.FilterState() != list.Filtering {
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return tea.Quit
+		case "enter":
+			return m.openWorkspace()
+		}
+	}
+	var cmd tea.Cmd
+	m.workspaceList, cmd = m.workspaceList.Update(msg)
+	return cmd
+}
+
+// openWorkspace makes the selected workspace the working directory and loads its
+// schemas from there, initializing it first when nothing is cached and it was never initialized
+func (m *Model) openWorkspace() tea.Cmd {
+	item, ok := m.workspaceList.SelectedItem().(WorkspaceItem)
+	if !ok {
+		return nil
+	}
+	m.workspace = item.path
+	m.status.SetWorkspace(item.path)
+
+	dir := m.workingDir()
+	if !terraform.HasValidProviderCache(dir, m.toolInfo) && config.NeedsInit(dir) {
+		if m.workspaceAutoInit {
+			return m.startInit()
+		}
+		m.stage = StageInitConfirm
+		return nil
+	}
+	return m.retryLoad()
+}
+
+// renderWorkspaceView renders the workspace picker
+func (m Model) renderWorkspaceView() string {
+	width := m.width - 4
+	if width < 20 {
+		width = 20
+	}
+	height := m.height - 4
+	if height < 6 {
+		height = 6
+	}
+	m.workspaceList.SetSize(width, height)
+
+	hint := errorHintStyle.Render("enter open • / filter • q quit")
+	view := focusedBorderStyle.Width(width).Height(height).Render(m.workspaceList.View())
+	return lipgloss.JoinVertical(lipgloss.Top, view, hint, m.status.Render())
+}
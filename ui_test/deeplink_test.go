@@ -0,0 +1,83 @@
+package ui_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+// cursorLine returns the tree line carrying the cursor marker and the line above it
+func cursorLine(view string) (string, string) {
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "> [ ] ") {
+			if i == 0 {
+				return line, ""
+			}
+			return line, lines[i-1]
+		}
+	}
+	return "", ""
+}
+
+func Test_DeepLink_OpensTreeOnPath(t *testing.T) {
+	// Set consistent color profile for stable output
+	lipgloss.SetColorProfile(0)
+
+	ps, err := ui.LoadProvidersSchemaFromFile(filepath.FromSlash("../testdata/schemas/aws_min.json"))
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	tfInfo := terraform.TerraformInfo{Binary: "terraform", Tool: "terraform", Registry: "registry.terraform.io"}
+
+	tests := []struct {
+		name     string
+		link     ui.DeepLink
+		cursor   string
+		previous string
+		mode     string
+	}{
+		{
+			name:     "nested argument",
+			link:     ui.DeepLink{Provider: "aws", Type: ui.ResourcesType, Entity: "aws_instance", Path: "root_block_device.volume_size"},
+			cursor:   "volume_size",
+			previous: "encrypted",
+			mode:     "Arguments",
+		},
+		{
+			name:   "computed attribute switches mode",
+			link:   ui.DeepLink{Type: ui.ResourcesType, Entity: "aws_instance", Path: "arn"},
+			cursor: "arn",
+			mode:   "Attributes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ui.NewModelFromSchemas(ps, tfInfo, "1.10.5", 120, 40)
+			m.SetDeepLink(tt.link)
+
+			var model tea.Model = m
+			model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+			model, _ = model.Update(model.Init()())
+
+			view := model.View()
+			if !strings.Contains(view, tt.mode) {
+				t.Fatalf("Expected tree in %s mode, got:\n%s", tt.mode, view)
+			}
+			line, previous := cursorLine(view)
+			if !strings.Contains(line, tt.cursor) {
+				t.Fatalf("Expected cursor on %q, got line %q in:\n%s", tt.cursor, line, view)
+			}
+			if tt.previous != "" && !strings.Contains(previous, tt.previous) {
+				t.Errorf("Expected %q above the cursor, got %q", tt.previous, previous)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package ui_test
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+// sgrSequence matches the styling escape sequences lipgloss writes
+var sgrSequence = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func Test_Tree_DetailsPane(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+
+	open := func(fixture string) tea.Model {
+		t.Helper()
+		ps, err := ui.LoadProvidersSchemaFromFile(filepath.FromSlash("../testdata/schemas/" + fixture))
+		if err != nil {
+			t.Fatalf("load fixture: %v", err)
+		}
+		var model tea.Model = ui.NewModelWithSchemas(ps, 120, 40)
+		model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		return model
+	}
+	key := func(model tea.Model, k string, times int) tea.Model {
+		for range times {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		}
+		return model
+	}
+	expect := func(view string, wants ...string) {
+		t.Helper()
+		view = sgrSequence.ReplaceAllString(view, "")
+		for _, want := range wants {
+			if !strings.Contains(view, want) {
+				t.Fatalf("Expected %q in the details, got:\n%s", want, view)
+			}
+		}
+	}
+
+	model := open("framework_min.json")
+	if strings.Contains(model.View(), "Reference:") {
+		t.Fatalf("Details should be hidden until toggled, got:\n%s", model.View())
+	}
+
+	// Markdown descriptions are rendered rather than shown as written
+	model = key(model, "d", 1)
+	view := sgrSequence.ReplaceAllString(model.View(), "")
+	expect(view, "Path: name", "Reference: fw_listener.main.name", "Type: string", "Flags: required", "naming rules", "https://example.com/naming")
+	if strings.Contains(view, "**") || strings.Contains(view, "`web`") {
+		t.Fatalf("Expected markdown to be rendered, got:\n%s", view)
+	}
+
+	model = key(model, "j", 1)
+	expect(model.View(), "nested attribute", "Reference: fw_listener.main.rules", "Type: list(object({")
+
+	// rules nests five attributes, shown down to the computed rule_id
+	model = key(model, "j", 6)
+	expect(model.View(), "Path: token", "sensitive", "write-only", "Deprecated: avoid")
+
+	model = key(model, "d", 1)
+	if strings.Contains(model.View(), "Reference:") {
+		t.Fatalf("Expected the details to be hidden again, got:\n%s", model.View())
+	}
+
+	// Blocks show their nesting and the type of the object they hold
+	model = key(open("aws_min.json"), "d", 1)
+	model = key(model, "j", 3)
+	expect(model.View(), "ebs_block_device block", "Nesting: set block", "Reference: aws_instance.main.ebs_block_device", "Type: set(object({")
+}
@@ -0,0 +1,204 @@
+package ui_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+// fakeTool writes a terraform stand-in that fails `providers schema` with an init
+// error until `init` has been run.
+func fakeTool(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "initialized")
+	script := `#!/bin/sh
+case "$1" in
+  init)
+    echo 'Initializing provider plugins...'
+    echo '- Installing hashicorp/null v3.2.4...'
+    touch "` + marker + `"; exit 0 ;;
+  version) echo '{"terraform_version":"1.10.5"}'; exit 0 ;;
+  providers)
+    if [ -f "` + marker + `" ]; then
+      echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{}}}}}}'
+      exit 0
+    fi
+    echo 'Error: Inconsistent dependency lock file' >&2
+    echo 'Run "terraform init" to install all required providers.' >&2
+    exit 1 ;;
+esac
+exit 1
+`
+	path := filepath.Join(dir, "terraform")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("write fake tool: %v", err)
+	}
+	return path
+}
+
+func Test_ErrorStage_ShowsFailureAndRecoversWithInit(t *testing.T) {
+	// Set consistent color profile for stable output
+	lipgloss.SetColorProfile(0)
+	// Keep the schema cache isolated from the user's home directory
+	t.Setenv("HOME", t.TempDir())
+
+	m := ui.NewModel(120, 40)
+	m.SetToolInfo(terraform.TerraformInfo{Binary: fakeTool(t), Tool: "terraform", Registry: "registry.terraform.io"})
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(model.Init()())
+
+	view := model.View()
+	for _, want := range []string{
+		"Failed to load provider schemas",
+		"providers schema -json",
+		"Inconsistent dependency lock file",
+		"Press i to run terraform init",
+		"i run init",
+	} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("Expected error view to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	// Run init from the error screen, then reload
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if cmd == nil {
+		t.Fatal("Expected init command")
+	}
+	model = runInit(t, model, cmd)
+
+	view = model.View()
+	if strings.Contains(view, "Failed to load provider schemas") {
+		t.Fatalf("Expected schemas to load after init, got:\n%s", view)
+	}
+	if !strings.Contains(view, "Resources (1 items)") {
+		t.Errorf("Expected the loaded provider's types, got:\n%s", view)
+	}
+}
+
+func Test_ErrorStage_BinaryNotFound(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+	t.Setenv("HOME", t.TempDir())
+
+	m := ui.NewModel(120, 40)
+	m.SetToolInfo(terraform.TerraformInfo{Binary: filepath.Join(t.TempDir(), "terraform"), Tool: "terraform"})
+
+	var model tea.Model = m
+	model, _ = model.Update(model.Init()())
+
+	view := model.View()
+	if !strings.Contains(view, "was not found") || !strings.Contains(view, "t switch to tofu") {
+		t.Fatalf("Expected binary-not-found guidance, got:\n%s", view)
+	}
+
+	// Retry goes back through loading and fails the same way without quitting
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if cmd == nil {
+		t.Fatal("Expected reload command")
+	}
+	model, _ = model.Update(cmd())
+	if !strings.Contains(model.View(), "Failed to load provider schemas") {
+		t.Errorf("Expected the error stage after a failed retry")
+	}
+}
+
+// runInit feeds streamed init output back into the model until init finishes, checks the
+// progress view along the way, and then performs the reload that follows a successful init.
+func runInit(t *testing.T, model tea.Model, cmd tea.Cmd) tea.Model {
+	t.Helper()
+	if view := model.View(); !strings.Contains(view, "Running terraform init") {
+		t.Fatalf("Expected init progress, got:\n%s", view)
+	}
+
+	// Two output lines, then the finished message which triggers the reload
+	model, cmd = model.Update(cmd())
+	model, cmd = model.Update(cmd())
+	if view := model.View(); !strings.Contains(view, "Installing hashicorp/null") {
+		t.Fatalf("Expected streamed init output, got:\n%s", view)
+	}
+	model, cmd = model.Update(cmd())
+	if cmd == nil {
+		t.Fatal("Expected reload after init")
+	}
+	model, _ = model.Update(cmd())
+	return model
+}
+
+func Test_InitConfirm_RunsInitBeforeLoading(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+	t.Setenv("HOME", t.TempDir())
+
+	m := ui.NewModel(120, 40)
+	m.SetToolInfo(terraform.TerraformInfo{Binary: fakeTool(t), Tool: "terraform", Registry: "registry.terraform.io"})
+	m.SetInitRequired(false)
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if cmd := model.Init(); cmd != nil {
+		t.Fatal("Expected no load before init is confirmed")
+	}
+	if view := model.View(); !strings.Contains(view, "Initialize working directory?") {
+		t.Fatalf("Expected init confirmation, got:\n%s", view)
+	}
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Fatal("Expected init command")
+	}
+	model = runInit(t, model, cmd)
+
+	if view := model.View(); !strings.Contains(view, "Resources (1 items)") {
+		t.Errorf("Expected schemas after init, got:\n%s", view)
+	}
+}
+
+func Test_ErrorStage_FailedInitSuggestsTheCause(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "terraform")
+	script := `#!/bin/sh
+case "$1" in
+  init)
+    echo 'Error: Failed to query available provider packages'
+    echo 'registry.terraform.io responded with 401 Unauthorized'
+    exit 1 ;;
+  version) echo '{"terraform_version":"1.10.5"}'; exit 0 ;;
+esac
+exit 1
+`
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatalf("write fake tool: %v", err)
+	}
+
+	m := ui.NewModel(120, 40)
+	m.SetToolInfo(terraform.TerraformInfo{Binary: bin, Tool: "terraform", Registry: "registry.terraform.io"})
+	m.SetInitRequired(false)
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	for cmd != nil {
+		model, cmd = model.Update(cmd())
+	}
+
+	// The failed command is init itself, which says nothing about missing providers
+	view := model.View()
+	if !strings.Contains(view, "terraform init failed") || !strings.Contains(view, "check your registry credentials") {
+		t.Fatalf("Expected the credentials suggestion, got:\n%s", view)
+	}
+	if strings.Contains(view, "Providers are missing") {
+		t.Errorf("Expected no init suggestion for a failed init, got:\n%s", view)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/terraconstructs/provider-explorer/internal/ui"
 )
@@ -37,8 +38,12 @@ func Test_HCL_Export_Arguments_To_Variables(t *testing.T) {
 		t.Errorf("Expected instance_type variable, got: %s", result)
 	}
 
-	if !strings.Contains(result, `type = any`) {
-		t.Errorf("Expected type declarations, got: %s", result)
+	if !strings.Contains(result, `type = string`) {
+		t.Errorf("Expected string type declarations, got: %s", result)
+	}
+
+	if !strings.Contains(result, `type = map(string)`) {
+		t.Errorf("Expected a map(string) type for tags, got: %s", result)
 	}
 
 	if !strings.Contains(result, `description = "Required argument`) {
@@ -181,3 +186,166 @@ func Test_EmptySchema_Handling(t *testing.T) {
 		t.Errorf("Expected no attributes message, got: %s", attrsResult)
 	}
 }
+
+func Test_ResolveSelectedPaths_Selectors(t *testing.T) {
+	ps, err := ui.LoadProvidersSchemaFromFile(filepath.FromSlash("../testdata/schemas/aws_min.json"))
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	instanceSchema := ps.Schemas["registry.terraform.io/hashicorp/aws"].ResourceSchemas["aws_instance"]
+
+	// Nested selector implicitly selects its parent block
+	paths, err := ui.ResolveSelectedPaths(instanceSchema.Block, []string{"ami", "root_block_device.volume_size"}, ui.ArgumentsMode)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	result := ui.ConvertSelectedArgumentsToHCLVariables(instanceSchema, paths)
+	if !strings.Contains(result, `variable "ami"`) {
+		t.Errorf("Expected ami variable, got: %s", result)
+	}
+	if !strings.Contains(result, `variable "root_block_device"`) || !strings.Contains(result, "type = object({ volume_size = optional(number) })") {
+		t.Errorf("Expected the block variable holding the nested selector, got: %s", result)
+	}
+	if strings.Contains(result, "encrypted") {
+		t.Errorf("Sibling of a nested selector should not be exported, got: %s", result)
+	}
+
+	// Block selector cascades to all descendants
+	paths, err = ui.ResolveSelectedPaths(instanceSchema.Block, []string{"ebs_block_device"}, ui.ArgumentsMode)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if len(paths) != 4 {
+		t.Errorf("Expected block and its 3 attributes, got: %v", paths)
+	}
+
+	// Unknown and mismatched paths are rejected
+	if _, err := ui.ResolveSelectedPaths(instanceSchema.Block, []string{"root_block_device.nope"}, ui.ArgumentsMode); err == nil {
+		t.Error("Expected error for unknown path")
+	}
+	if _, err := ui.ResolveSelectedPaths(instanceSchema.Block, []string{"arn"}, ui.ArgumentsMode); err == nil {
+		t.Error("Expected error for computed attribute in arguments mode")
+	}
+	if _, err := ui.ResolveSelectedPaths(instanceSchema.Block, []string{"ami"}, ui.AttributesMode); err == nil {
+		t.Error("Expected error for argument in attributes mode")
+	}
+}
+
+func Test_HCL_Export_NestedAttributes(t *testing.T) {
+	ps, err := ui.LoadProvidersSchemaFromFile(filepath.FromSlash("../testdata/schemas/framework_min.json"))
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	listenerSchema := ps.Schemas["registry.terraform.io/example/fw"].ResourceSchemas["fw_listener"]
+
+	// A nested attribute selector cascades and exports as one variable of object type
+	paths, err := ui.ResolveSelectedPaths(listenerSchema.Block, []string{"rules"}, ui.ArgumentsMode)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if len(paths) != 6 {
+		t.Errorf("Expected rules and its 5 nested attributes, got: %v", paths)
+	}
+	result := ui.ConvertSelectedArgumentsToHCLVariables(listenerSchema, paths)
+	want := "type = list(object({ cidr = optional(string), port = number, target = optional(object({ id = string })) }))"
+	if !strings.Contains(result, want) {
+		t.Errorf("Expected %q, got: %s", want, result)
+	}
+	if strings.Count(result, "variable ") != 1 {
+		t.Errorf("Nested attributes should be covered by the rules variable, got: %s", result)
+	}
+
+	// Selecting a nested attribute only keeps it in the object
+	paths, err = ui.ResolveSelectedPaths(listenerSchema.Block, []string{"rules.port"}, ui.ArgumentsMode)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	result = ui.ConvertSelectedArgumentsToHCLVariables(listenerSchema, paths)
+	if !strings.Contains(result, "type = list(object({ port = number }))") {
+		t.Errorf("Expected only port in the rules object, got: %s", result)
+	}
+
+	// Outputs reference nested attributes through splats and map values
+	paths, err = ui.ResolveSelectedPaths(listenerSchema.Block, []string{"status", "backends"}, ui.AttributesMode)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	result = ui.ConvertSelectedAttributesToHCLOutputs("fw_listener", listenerSchema, "registry.terraform.io/example/fw", "main", paths)
+	for _, want := range []string{
+		"value = fw_listener.main.status.state",
+		"value = fw_listener.main.status.endpoints[*].address",
+		"value = flatten(fw_listener.main.status.endpoints[*].ports)[*].number",
+		"value = values(fw_listener.main.backends)[*].weight",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q, got: %s", want, result)
+		}
+	}
+	if strings.Contains(result, `output "status"`) {
+		t.Errorf("A nested attribute with selected nested attributes should be exported through them, got: %s", result)
+	}
+
+	// Without selected nested attributes the whole value is exported
+	result = ui.ConvertSelectedAttributesToHCLOutputs("fw_listener", listenerSchema, "registry.terraform.io/example/fw", "main", [][]string{{"status"}})
+	if !strings.Contains(result, "value = fw_listener.main.status\n") || !strings.Contains(result, "# object({ endpoints = optional(list(object({ address = optional(string), ports = optional(list(object({ number = optional(number) }))) }))), state = optional(string) })") {
+		t.Errorf("Expected the whole status object, got: %s", result)
+	}
+}
+
+func Test_HCL_Export_BlockNesting(t *testing.T) {
+	attrs := func() *tfjson.SchemaBlock {
+		return &tfjson.SchemaBlock{Attributes: map[string]*tfjson.SchemaAttribute{
+			"size": {AttributeType: cty.Number, Optional: true},
+			"id":   {AttributeType: cty.String, Computed: true},
+		}}
+	}
+	serverSchema := &tfjson.Schema{Block: &tfjson.SchemaBlock{
+		Attributes: map[string]*tfjson.SchemaAttribute{
+			"name": {AttributeType: cty.String, Required: true},
+		},
+		NestedBlocks: map[string]*tfjson.SchemaBlockType{
+			"boot_disk": {NestingMode: tfjson.SchemaNestingModeList, MinItems: 1, MaxItems: 1, Block: attrs()},
+			"disk":      {NestingMode: tfjson.SchemaNestingModeSet, Block: attrs()},
+			"network": {NestingMode: tfjson.SchemaNestingModeList, MaxItems: 1, Block: &tfjson.SchemaBlock{
+				NestedBlocks: map[string]*tfjson.SchemaBlockType{
+					"access": {NestingMode: tfjson.SchemaNestingModeList, Block: attrs()},
+				},
+			}},
+		},
+	}}
+
+	// Blocks limited to one item are single objects, the others keep their nesting
+	paths, err := ui.ResolveSelectedPaths(serverSchema.Block, []string{"boot_disk", "disk", "network"}, ui.ArgumentsMode)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	result := ui.ConvertSelectedArgumentsToHCLVariables(serverSchema, paths)
+	for _, want := range []string{
+		"variable \"boot_disk\" {\n  type = object({ size = optional(number) })\n  description = \"Required block for boot_disk\"\n}",
+		"variable \"disk\" {\n  type = set(object({ size = optional(number) }))\n  description = \"Optional block for disk\"\n  default = null\n}",
+		"variable \"network\" {\n  type = object({ access = optional(list(object({ size = optional(number) }))) })",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q, got: %s", want, result)
+		}
+	}
+	if strings.Count(result, "variable ") != 3 {
+		t.Errorf("Nested arguments should be covered by their block variables, got: %s", result)
+	}
+
+	// Outputs unwrap blocks limited to one item and splat the others
+	paths, err = ui.ResolveSelectedPaths(serverSchema.Block, []string{"boot_disk.id", "disk.id", "network.access.id"}, ui.AttributesMode)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	result = ui.ConvertSelectedAttributesToHCLOutputs("example_server", serverSchema, "registry.terraform.io/example/cloud", "main", paths)
+	for _, want := range []string{
+		"value = one(example_server.main.boot_disk).id",
+		"value = example_server.main.disk[*].id",
+		"value = one(example_server.main.network).access[*].id",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q, got: %s", want, result)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package ui_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+func Test_Modules_ShowsVariablesAndOutputs(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TF_DATA_DIR", "")
+
+	root := t.TempDir()
+	t.Chdir(root)
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.tf", "module \"app\" {\n  source = \"./modules/app\"\n}\n")
+	write("modules/app/main.tf", `variable "name" {
+  type = string
+}
+
+variable "token" {
+  type      = string
+  default   = "secret"
+  sensitive = true
+}
+
+output "name" {
+  value = var.name
+}
+
+output "url" {
+  value       = "https://example.com"
+  description = "Where the app is served"
+}
+`)
+	write("bin/terraform", `#!/bin/sh
+case "$1" in
+  version) echo '{"terraform_version":"1.10.5"}'; exit 0 ;;
+  providers) echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{}}}}}}'; exit 0 ;;
+esac
+exit 1
+`)
+
+	m := ui.NewModel(120, 40)
+	m.SetToolInfo(terraform.TerraformInfo{Binary: filepath.Join(root, "bin", "terraform"), Tool: "terraform", Registry: "registry.terraform.io"})
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(model.Init()())
+	view := model.View()
+	if !strings.Contains(view, "Modules (1 items)") {
+		t.Fatalf("Expected the Modules category, got:\n%s", view)
+	}
+
+	key := func(k string) {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "space":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		}
+		model, _ = model.Update(msg)
+	}
+	for range 4 {
+		key("j")
+	}
+	key("enter")
+	view = model.View()
+	if !strings.Contains(view, "2 variables, 2 outputs · ./modules/app") {
+		t.Fatalf("Expected the app module, got:\n%s", view)
+	}
+
+	// Variables are the arguments of a module, with their defaults
+	key("enter")
+	view = model.View()
+	for _, want := range []string{"name (string) [required]", "token (string) [optional]", `= "secret"`, "[sensitive]"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("Expected %q in the tree, got:\n%s", want, view)
+		}
+	}
+	key("space")
+	key("e")
+	view = model.View()
+	if !strings.Contains(view, `variable "name"`) || !strings.Contains(view, "type = string") || strings.Contains(view, `variable "token"`) {
+		t.Fatalf("Expected the name variable to be exported, got:\n%s", view)
+	}
+
+	// Outputs are its attributes, referenced through the module call
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	key("a")
+	view = model.View()
+	if !strings.Contains(view, "url (any) [computed]") {
+		t.Fatalf("Expected the outputs, got:\n%s", view)
+	}
+	key("j")
+	key("space")
+	key("e")
+	view = model.View()
+	if !strings.Contains(view, "value = module.app.url") || !strings.Contains(view, "Where the app is served") {
+		t.Fatalf("Expected the url output to be exported, got:\n%s", view)
+	}
+}
+
+func Test_Modules_DefaultsNestedCallsAndErrors(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TF_DATA_DIR", "")
+
+	root := t.TempDir()
+	t.Chdir(root)
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.tf", "module \"app\" {\n  source = \"./modules/app\"\n}\n")
+	write("modules/app/main.tf", `variable "tags" {
+  type    = map(string)
+  default = { team = "web", cost = "$${var.x}" }
+}
+
+module "db" {
+  source = "./db"
+}
+`)
+	write("modules/app/db/main.tf", "output \"host\" {\n  value = \"db\"\n}\n")
+	write(".terraform/modules/modules.json", `{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"app","Source":"./modules/app","Dir":"modules/app"},{"Key":"app.db","Source":"./db","Dir":"modules/app/db"}]}`)
+	write("bin/terraform", `#!/bin/sh
+case "$1" in
+  version) echo '{"terraform_version":"1.10.5"}'; exit 0 ;;
+  providers) echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{}}}}}}'; exit 0 ;;
+esac
+exit 1
+`)
+
+	m := ui.NewModel(120, 40)
+	m.SetToolInfo(terraform.TerraformInfo{Binary: filepath.Join(root, "bin", "terraform"), Tool: "terraform", Registry: "registry.terraform.io"})
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(model.Init()())
+
+	key := func(k string) tea.Cmd {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "space":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		}
+		var cmd tea.Cmd
+		model, cmd = model.Update(msg)
+		return cmd
+	}
+	for range 4 {
+		key("j")
+	}
+	key("enter")
+
+	// Exported variables keep their defaults, written as HCL
+	key("enter")
+	key("space")
+	key("e")
+	view := model.View()
+	for _, want := range []string{`variable "tags"`, `cost = "$${var.x}"`, `team = "web"`} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("Expected %q in the export, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "default = null") {
+		t.Fatalf("Expected the real default, got:\n%s", view)
+	}
+
+	// module.db is not reachable from the root module, so its outputs are not exported
+	key("esc")
+	key("esc")
+	key("esc")
+	key("j")
+	key("enter")
+	key("a")
+	key("space")
+	key("e")
+	view = model.View()
+	if strings.Contains(view, "module.db") || !strings.Contains(view, "app.db is not called by the root") {
+		t.Fatalf("Expected the export of app.db outputs to be refused, got:\n%s", view)
+	}
+
+	// A configuration whose modules cannot be read still shows its schemas
+	write("main.tf", "module \"app\" {\n")
+	m = ui.NewModel(120, 40)
+	m.SetToolInfo(terraform.TerraformInfo{Binary: filepath.Join(root, "bin", "terraform"), Tool: "terraform", Registry: "registry.terraform.io"})
+	model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(model.Init()())
+	view = model.View()
+	if !strings.Contains(view, "Resources (1 items)") || !strings.Contains(view, "modules not loaded") {
+		t.Fatalf("Expected the schemas and a notice about the modules, got:\n%s", view)
+	}
+}
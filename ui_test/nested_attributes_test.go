@@ -0,0 +1,45 @@
+package ui_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+func Test_Tree_NestedAttributesAreSelectableSubtrees(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+
+	ps, err := ui.LoadProvidersSchemaFromFile(filepath.FromSlash("../testdata/schemas/framework_min.json"))
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+
+	var model tea.Model = ui.NewModelWithSchemas(ps, 120, 40)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	view := model.View()
+	for _, want := range []string{"rules (list(object)) [optional]", "port (number) [required]", "target (object) [optional]", "id (string) [required]"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("Expected %q in the tree, got:\n%s", want, view)
+		}
+	}
+
+	// Selecting the nested attribute selects what it nests
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if view := model.View(); !strings.Contains(view, "Selected: 6 nodes") {
+		t.Fatalf("Expected rules and its nested attributes to be selected, got:\n%s", view)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	view = model.View()
+	if !strings.Contains(view, `variable "rules"`) || !strings.Contains(view, "port = number") {
+		t.Fatalf("Expected the rules variable, got:\n%s", view)
+	}
+}
@@ -0,0 +1,118 @@
+package ui_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+// upgradingTool writes a terraform stand-in that reports null 3.2.3 with a single
+// argument on its first schema fetch, and null 3.2.4 with an extra argument afterwards.
+func upgradingTool(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "upgraded")
+	script := `#!/bin/sh
+case "$1" in
+  version)
+    if [ -f "` + marker + `" ]; then
+      echo '{"terraform_version":"1.10.5","provider_selections":{"registry.terraform.io/hashicorp/null":"3.2.4"}}'
+    else
+      echo '{"terraform_version":"1.10.5","provider_selections":{"registry.terraform.io/hashicorp/null":"3.2.3"}}'
+    fi
+    exit 0 ;;
+  providers)
+    if [ -f "` + marker + `" ]; then
+      echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{"attributes":{"triggers":{"type":["map","string"],"optional":true},"when":{"type":"string","optional":true}}}}}}}}'
+    else
+      echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{"attributes":{"triggers":{"type":["map","string"],"optional":true}}}}}}}}'
+      touch "` + marker + `"
+    fi
+    exit 0 ;;
+esac
+exit 1
+`
+	path := filepath.Join(dir, "terraform")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("write fake tool: %v", err)
+	}
+	return path
+}
+
+// runCmd executes cmd, expanding batches, and returns the resulting messages
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, runCmd(c)...)
+		}
+		return msgs
+	}
+	if msg == nil {
+		return nil
+	}
+	return []tea.Msg{msg}
+}
+
+func Test_Refresh_OffersUpdatedSchemasAndKeepsPosition(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+	t.Setenv("HOME", t.TempDir())
+	tfInfo := terraform.TerraformInfo{Binary: upgradingTool(t), Tool: "terraform", Registry: "registry.terraform.io"}
+
+	// The first session fetches from the tool and populates the cache
+	first := ui.NewModel(120, 40)
+	first.SetToolInfo(tfInfo)
+	var model tea.Model = first
+	model, cmd := model.Update(model.Init()())
+	if len(runCmd(cmd)) != 0 {
+		t.Fatal("Expected no background refresh for schemas fetched from the tool")
+	}
+
+	// The second session opens from the cache on the linked argument
+	second := ui.NewModel(120, 40)
+	second.SetToolInfo(tfInfo)
+	second.SetDeepLink(ui.DeepLink{Type: ui.ResourcesType, Entity: "null_resource", Path: "triggers"})
+	model = second
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, cmd = model.Update(model.Init()())
+
+	view := model.View()
+	if cursor, _ := cursorLine(view); !strings.Contains(cursor, "triggers") || strings.Contains(view, "when") {
+		t.Fatalf("Expected the cached tree on triggers, got:\n%s", view)
+	}
+
+	msgs := runCmd(cmd)
+	if len(msgs) == 0 {
+		t.Fatal("Expected a background refresh for schemas opened from the cache")
+	}
+	for _, msg := range msgs {
+		model, _ = model.Update(msg)
+	}
+	view = model.View()
+	if !strings.Contains(view, "schemas updated: null 3.2.3 → 3.2.4") {
+		t.Fatalf("Expected the refresh notice, got:\n%s", view)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	view = model.View()
+	if strings.Contains(view, "schemas updated") {
+		t.Errorf("Expected the notice to clear after reloading, got:\n%s", view)
+	}
+	if !strings.Contains(view, "when") || !strings.Contains(view, "null_resource") {
+		t.Errorf("Expected the refreshed tree, got:\n%s", view)
+	}
+	if cursor, _ := cursorLine(view); !strings.Contains(cursor, "triggers") {
+		t.Errorf("Expected the cursor to stay on triggers, got:\n%s", view)
+	}
+}
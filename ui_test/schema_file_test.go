@@ -0,0 +1,48 @@
+package ui_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+func Test_SchemaFile_UnknownVersionEnablesPresentTypes(t *testing.T) {
+	// Set consistent color profile for stable output
+	lipgloss.SetColorProfile(0)
+
+	ps, err := ui.LoadProvidersSchemaFromFile(filepath.FromSlash("../testdata/schemas/aws_min.json"))
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+
+	// No version detected, as when no Terraform binary is installed
+	tfInfo := terraform.TerraformInfo{Binary: "terraform", Tool: "terraform", Registry: "registry.terraform.io"}
+	var m tea.Model = ui.NewModelFromSchemas(ps, tfInfo, "", 120, 30)
+
+	// Drive the regular load flow
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m, _ = m.Update(m.Init()())
+
+	view := m.View()
+	if !strings.Contains(view, "Provider Functions (2 items)") {
+		t.Fatalf("Expected type counts for the auto-selected provider, got:\n%s", view)
+	}
+
+	// The single provider is auto-selected, so focus is on the types list. Provider Functions
+	// are present in the document, so they stay enabled despite the unknown version.
+	for i := 0; i < 3; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	view = m.View()
+	if !strings.Contains(view, "aws_partition") {
+		t.Errorf("Expected provider functions to be browsable, got:\n%s", view)
+	}
+}
@@ -0,0 +1,81 @@
+package ui_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+func Test_Tree_FoldingBlocks(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+
+	ps, err := ui.LoadProvidersSchemaFromFile(filepath.FromSlash("../testdata/schemas/aws_min.json"))
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+
+	var model tea.Model = ui.NewModelWithSchemas(ps, 120, 40)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	key := func(k string) {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "space":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		case "left":
+			msg = tea.KeyMsg{Type: tea.KeyLeft}
+		}
+		model, _ = model.Update(msg)
+	}
+
+	// ami, instance_type and tags come before the ebs_block_device block
+	for range 3 {
+		key("j")
+	}
+	key("h")
+	view := model.View()
+	if !strings.Contains(view, "(3 children)") || strings.Contains(view, "device_name") {
+		t.Fatalf("Expected ebs_block_device to be folded, got:\n%s", view)
+	}
+
+	// Selecting a folded block selects what it holds
+	key("space")
+	if view := model.View(); !strings.Contains(view, "Selected: 4 nodes") {
+		t.Fatalf("Expected the block and its attributes to be selected, got:\n%s", view)
+	}
+
+	key("l")
+	if view := model.View(); !strings.Contains(view, "device_name") {
+		t.Fatalf("Expected ebs_block_device to be unfolded, got:\n%s", view)
+	}
+
+	// Folding from a nested attribute goes to the block first, then folds it
+	key("j")
+	key("left")
+	key("left")
+	if view := model.View(); !strings.Contains(view, "(3 children)") {
+		t.Fatalf("Expected ebs_block_device to be folded from its attribute, got:\n%s", view)
+	}
+
+	key("-")
+	if view := model.View(); strings.Count(view, "children)") != 2 || strings.Contains(view, "volume_size") {
+		t.Fatalf("Expected both blocks to be folded, got:\n%s", view)
+	}
+	key("+")
+	if view := model.View(); strings.Contains(view, "children)") || !strings.Contains(view, "encrypted") {
+		t.Fatalf("Expected every block to be unfolded, got:\n%s", view)
+	}
+	key("1")
+	if view := model.View(); strings.Count(view, "children)") != 2 {
+		t.Fatalf("Expected only the top level to be shown, got:\n%s", view)
+	}
+	if view := model.View(); !strings.Contains(view, "Selected: 4 nodes") {
+		t.Fatalf("Expected the selection to survive folding, got:\n%s", view)
+	}
+}
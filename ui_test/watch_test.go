@@ -0,0 +1,118 @@
+package ui_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+// startCmd runs every command of a (possibly batched) cmd in the background and posts
+// the resulting messages to msgs. Commands that block, such as the watcher, simply
+// never post.
+func startCmd(cmd tea.Cmd, msgs chan<- tea.Msg) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				startCmd(c, msgs)
+			}
+			return
+		}
+		if msg != nil {
+			msgs <- msg
+		}
+	}()
+}
+
+// runUntil feeds messages into the model until its view satisfies done
+func runUntil(t *testing.T, model tea.Model, msgs chan tea.Msg, done func(string) bool) tea.Model {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for !done(model.View()) {
+		select {
+		case msg := <-msgs:
+			var cmd tea.Cmd
+			model, cmd = model.Update(msg)
+			startCmd(cmd, msgs)
+		case <-deadline:
+			t.Fatalf("timed out, last view:\n%s", model.View())
+		}
+	}
+	return model
+}
+
+func Test_Watch_ReloadsSchemasAndKeepsSelection(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+	t.Setenv("HOME", t.TempDir())
+
+	// The stand-in tool adds an argument once main.tf mentions it
+	dir := t.TempDir()
+	t.Chdir(dir)
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(os.WriteFile("main.tf", []byte("resource \"null_resource\" \"this\" {}\n"), 0644))
+	toolPath := filepath.Join(t.TempDir(), "terraform")
+	must(os.WriteFile(toolPath, []byte(`#!/bin/sh
+case "$1" in
+  version) echo '{"terraform_version":"1.10.5"}'; exit 0 ;;
+  providers)
+    if grep -q when main.tf; then
+      echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{"attributes":{"triggers":{"type":["map","string"],"optional":true},"when":{"type":"string","optional":true}}}}}}}}'
+    else
+      echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{"attributes":{"triggers":{"type":["map","string"],"optional":true}}}}}}}}'
+    fi
+    exit 0 ;;
+esac
+exit 1
+`), 0755))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := ui.NewModel(120, 40)
+	m.SetToolInfo(terraform.TerraformInfo{Binary: toolPath, Tool: "terraform", Registry: "registry.terraform.io"})
+	m.SetDeepLink(ui.DeepLink{Type: ui.ResourcesType, Entity: "null_resource", Path: "triggers"})
+	m.EnableWatch(ctx, 10*time.Millisecond)
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	msgs := make(chan tea.Msg, 16)
+	startCmd(model.Init(), msgs)
+
+	model = runUntil(t, model, msgs, func(view string) bool {
+		cursor, _ := cursorLine(view)
+		return strings.Contains(cursor, "triggers")
+	})
+	if strings.Contains(model.View(), "when") {
+		t.Fatalf("Expected the initial schema, got:\n%s", model.View())
+	}
+
+	must(os.WriteFile("main.tf", []byte("resource \"null_resource\" \"this\" {\n  when = \"now\"\n}\n"), 0644))
+
+	model = runUntil(t, model, msgs, func(view string) bool {
+		return strings.Contains(view, "schemas reloaded")
+	})
+	view := model.View()
+	if !strings.Contains(view, "when") {
+		t.Errorf("Expected the reloaded tree, got:\n%s", view)
+	}
+	if cursor, _ := cursorLine(view); !strings.Contains(cursor, "triggers") {
+		t.Errorf("Expected the cursor to stay on triggers, got:\n%s", view)
+	}
+}
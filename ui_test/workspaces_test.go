@@ -0,0 +1,90 @@
+package ui_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/terraconstructs/provider-explorer/internal/terraform"
+	"github.com/terraconstructs/provider-explorer/internal/ui"
+)
+
+func Test_WorkspacePicker_OpensRootModules(t *testing.T) {
+	lipgloss.SetColorProfile(0)
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	t.Chdir(root)
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("envs/dev/main.tf", "terraform {}\n")
+	write("envs/prod/main.tf", "terraform {}\n")
+	write("envs/prod/.terraform.lock.hcl", "provider \"registry.terraform.io/hashicorp/null\" {\n  version = \"3.2.4\"\n}\n")
+	write("envs/prod/.terraform/providers/.keep", "")
+	write("bin/terraform", `#!/bin/sh
+case "$1" in
+  version) echo '{"terraform_version":"1.10.5"}'; exit 0 ;;
+  providers) echo '{"format_version":"1.0","provider_schemas":{"registry.terraform.io/hashicorp/null":{"resource_schemas":{"null_resource":{"version":0,"block":{}}}}}}'; exit 0 ;;
+esac
+exit 1
+`)
+
+	m := ui.NewModel(120, 40)
+	m.SetToolInfo(terraform.TerraformInfo{Binary: filepath.Join(root, "bin", "terraform"), Tool: "terraform", Registry: "registry.terraform.io"})
+	m.SetWorkspaces(root, []string{"envs/dev", "envs/prod"}, false)
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if cmd := model.Init(); cmd != nil {
+		t.Fatal("Expected no load before a workspace is picked")
+	}
+	view := model.View()
+	for _, want := range []string{"envs/dev", "no lockfile", "envs/prod", "null 3.2.4"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("Expected %q in the picker, got:\n%s", want, view)
+		}
+	}
+
+	// The initialized workspace loads right away
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected a schema load")
+	}
+	model, _ = model.Update(cmd())
+	view = model.View()
+	if !strings.Contains(view, "envs/prod") || !strings.Contains(view, "Resources (1 items)") {
+		t.Fatalf("Expected the schemas of envs/prod, got:\n%s", view)
+	}
+	// Schemas are loaded from the workspace without changing the process directory
+	if wd, _ := os.Getwd(); wd != root {
+		t.Errorf("Expected the working directory to stay %s, got %s", root, wd)
+	}
+
+	// Escape leads back through the provider list to the picker
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	view = model.View()
+	if !strings.Contains(view, "Workspaces in") || strings.Contains(view, "Resources (1 items)") {
+		t.Fatalf("Expected the picker again, got:\n%s", view)
+	}
+
+	// The uninitialized workspace asks for init first
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := model.View(); !strings.Contains(view, "Initialize working directory?") || !strings.Contains(view, filepath.Join("envs", "dev")) {
+		t.Errorf("Expected init confirmation for envs/dev, got:\n%s", view)
+	}
+}